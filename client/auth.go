@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/chinahtl/tencent-doc-sdk/constant"
 	"github.com/chinahtl/tencent-doc-sdk/model"
@@ -26,7 +29,6 @@ import (
   - 包含client_id, redirect_uri等必要参数
 */
 func (c *Client) GetAuthURL() string {
-	u, _ := url.Parse(constant.AuthEndpoint)
 	query := url.Values{}
 	query.Set("client_id", c.config.ClientID)
 	query.Set("redirect_uri", c.config.RedirectURI)
@@ -39,6 +41,7 @@ func (c *Client) GetAuthURL() string {
 		query.Set("state", util.GenerateRandomString(16))
 	}
 
+	u, _ := url.Parse(c.config.AuthEndpoint) // 创建客户端时已校验
 	u.RawQuery = query.Encode()
 	return u.String()
 }
@@ -58,8 +61,9 @@ func (c *Client) GetAuthURL() string {
 
   特殊处理：
   - 如果配置了InitialToken，则直接返回初始令牌
+  - 换取成功后令牌会设置到客户端，并在配置了TokenStore时按UserID写回
 */
-func (c *Client) ExchangeToken(ctx context.Context, code string) (*model.TokenResponse, error) {
+func (c *Client) ExchangeToken(ctx context.Context, code string, opts ...CallOption) (*model.TokenResponse, error) {
 
 	if c.config.InitialToken != nil && c.config.InitialToken.AccessToken != "" {
 		return &model.TokenResponse{
@@ -79,10 +83,21 @@ func (c *Client) ExchangeToken(ctx context.Context, code string) (*model.TokenRe
 	params.Set("redirect_uri", c.config.RedirectURI)
 
 	var result model.TokenResponse
-	err := util.PostForm(ctx, c.httpClient, constant.TokenEndpoint, params, &result)
+	err := c.instrument(ctx, "ExchangeToken", nil, opts, func(ctx context.Context) error {
+		return c.send(ctx, c.doToken, &util.Request{
+			Method:   http.MethodPost,
+			Endpoint: c.config.TokenEndpoint,
+			Form:     params,
+		}, &result)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("exchange token failed: %w", err)
 	}
+	stampExpiresAt(&result.Token)
+
+	if err := c.storeToken(ctx, &result.Token); err != nil {
+		return nil, err
+	}
 
 	return &result, nil
 }
@@ -112,7 +127,43 @@ func (c *Client) ExchangeToken(ctx context.Context, code string) (*model.TokenRe
 //   - 刷新令牌本身也有有效期，过期后将无法使用
 //   - 新的刷新令牌可能与原令牌相同或不同
 //   - 建议在访问令牌过期前主动刷新
-func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*model.TokenResponse, error) {
+//   - 新令牌按响应中的 UserID（缺失时为 Manager 绑定的用户）写回 TokenStore
+//   - 仅当 refreshToken 属于客户端当前持有的令牌或新令牌属于客户端当前用户时，新令牌才会设置到客户端
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string, opts ...CallOption) (*model.TokenResponse, error) {
+	result, err := c.requestRefresh(ctx, refreshToken, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 旧的刷新令牌已失效，新令牌必须写回 TokenStore；仅当属于客户端当前用户时才替换客户端持有的令牌
+	current := c.currentToken()
+	held := current != nil && current.RefreshToken == refreshToken
+	token := &result.Token
+	if held {
+		token = mergeRefreshed(current, token)
+	} else if token.UserID == "" {
+		cp := *token
+		cp.UserID = c.openID
+		token = &cp
+	}
+
+	owner := c.openID
+	if owner == "" && current != nil {
+		owner = current.UserID
+	}
+	if held || (owner != "" && owner == token.UserID) {
+		err = c.storeToken(ctx, token)
+	} else {
+		err = c.saveToken(ctx, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// requestRefresh 调用刷新令牌接口，不修改客户端状态
+func (c *Client) requestRefresh(ctx context.Context, refreshToken string, opts []CallOption) (*model.TokenResponse, error) {
 	params := url.Values{}
 	params.Set("client_id", c.config.ClientID)
 	params.Set("client_secret", c.config.ClientSecret)
@@ -120,10 +171,62 @@ func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*model.
 	params.Set("grant_type", "refresh_token")
 
 	var result model.TokenResponse
-	err := util.PostForm(ctx, c.httpClient, constant.TokenEndpoint, params, &result)
+	err := c.instrument(ctx, "RefreshToken", nil, opts, func(ctx context.Context) error {
+		return c.send(ctx, c.doToken, &util.Request{
+			Method:   http.MethodPost,
+			Endpoint: c.config.TokenEndpoint,
+			Form:     params,
+		}, &result)
+	})
+	c.config.Metrics.ObserveTokenRefresh(err)
 	if err != nil {
 		return nil, fmt.Errorf("refresh token failed: %w", err)
 	}
+	stampExpiresAt(&result.Token)
 
 	return &result, nil
 }
+
+// doToken 发送令牌请求并解析到 result
+//
+// 令牌接口成功时不返回 {ret,msg} 结构：ret 非0时返回 *model.APIError；缺少 access_token 时
+// 返回同时匹配 model.ErrInvalidToken 和 *model.APIError 的错误，Msg 为响应中的 error/error_description 或响应体。
+func (c *Client) doToken(ctx context.Context, req *util.Request, result interface{}) (*util.Response, error) {
+	resp, err := c.executor.Do(ctx, req, result)
+	if err != nil {
+		return resp, err
+	}
+
+	var body struct {
+		Ret              int    `json:"ret"`
+		Msg              string `json:"msg"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		return resp, err
+	}
+	apiErr := &model.APIError{
+		StatusCode: resp.StatusCode,
+		Ret:        body.Ret,
+		Msg:        body.Msg,
+		Endpoint:   req.Endpoint,
+		RequestID:  resp.RequestID,
+	}
+	if body.Ret != 0 {
+		return resp, apiErr
+	}
+	if token, ok := result.(*model.TokenResponse); !ok || token.AccessToken != "" {
+		return resp, nil
+	}
+
+	switch {
+	case body.Error != "" && body.ErrorDescription != "":
+		apiErr.Msg = body.Error + ": " + body.ErrorDescription
+	case body.Error != "":
+		apiErr.Msg = body.Error
+	case apiErr.Msg == "":
+		apiErr.Msg = strings.TrimSpace(string(resp.Body))
+	}
+	return resp, fmt.Errorf("%w: no access token in response: %w", model.ErrInvalidToken, apiErr)
+}
@@ -3,69 +3,162 @@ package client
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/chinahtl/tencent-doc-sdk/config"
 	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/store"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+	"github.com/chinahtl/tencent-doc-sdk/util"
 )
 
 // TencentDocClient 腾讯文档客户端接口
 type TencentDocClient interface {
 	// 授权相关
 	GetAuthURL() string
-	ExchangeToken(ctx context.Context, code string) (*model.TokenResponse, error)
-	RefreshToken(ctx context.Context, refreshToken string) (*model.TokenResponse, error)
+	ExchangeToken(ctx context.Context, code string, opts ...CallOption) (*model.TokenResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string, opts ...CallOption) (*model.TokenResponse, error)
 
 	// 用户相关
-	GetUserInfo(ctx context.Context) (*model.UserInfo, error)
+	GetUserInfo(ctx context.Context, opts ...CallOption) (*model.UserInfo, error)
 	// 文档操作
-	ListDocuments(ctx context.Context, params *model.ListParams) (*model.ListDocumentsResponse, error)
-	SearchDocuments(ctx context.Context, params *model.SearchParams) (*model.SearchDocumentsResponse, error)
-	GetFileMetadata(ctx context.Context, fileID string) (*model.FileMetadataResponse, error)
+	ListDocuments(ctx context.Context, params *model.ListParams, opts ...CallOption) (*model.ListDocumentsResponse, error)
+	SearchDocuments(ctx context.Context, params *model.SearchParams, opts ...CallOption) (*model.SearchDocumentsResponse, error)
+	GetFileMetadata(ctx context.Context, fileID string, opts ...CallOption) (*model.FileMetadataResponse, error)
 
 	// 导出相关
-	ExportDocument(ctx context.Context, docID string, req *model.ExportRequest) (*model.ExportResponse, error)
-	GetExportProgress(ctx context.Context, docID string, operationID string) (*model.ExportProgressResponse, error)
+	ExportDocument(ctx context.Context, docID string, req *model.ExportRequest, opts ...CallOption) (*model.ExportResponse, error)
+	GetExportProgress(ctx context.Context, docID string, operationID string, opts ...CallOption) (*model.ExportProgressResponse, error)
 }
 
 // Client 实现 TencentDocClient 接口
+//
+// Client 并发安全，可在多个协程间共享。
 type Client struct {
 	config     *config.Config
-	httpClient *http.Client
-	token      *model.Token
+	executor   *util.Executor // OpenAPI 请求执行器
+	downloader *util.Executor // 文件下载执行器
+	openID     string         // 由 Manager 创建时绑定的用户
+
+	mu        sync.RWMutex // 保护 token 的读写
+	token     *model.Token
+	refreshMu sync.Mutex // 保证同一时间只有一个刷新流程
+
+	exports *exportTracker // 记录导出任务的开始时间，用于统计导出耗时，由 ForToken 创建的副本共享
 }
 
 // 确保 Client 实现 TencentDocClient 接口
 var _ TencentDocClient = (*Client)(nil)
 
-// WithToken 设置访问令牌
+// WithToken 设置访问令牌，会替换客户端当前持有的令牌
+//
+// 若 token 未设置 ExpiresAt 但设置了 ExpiresIn，则视为刚刚签发并据此计算过期时间。
+// 多个协程共享客户端时，可使用 ForToken 派生绑定其他令牌的副本，或使用 ContextWithToken 为单次调用指定令牌。
 func (c *Client) WithToken(token *model.Token) *Client {
-	c.token = token
+	c.setToken(token)
 	return c
 }
 
 // NewClient 创建新的客户端实例
+//
+// 配置的服务端点无效（见 config.Config.Validate）时 panic。
 func NewClient(opts ...config.Option) *Client {
+	cfg := buildConfig(opts)
+	executor, downloader := newExecutors(cfg)
+	client := newClient(cfg, executor, downloader, "")
+
+	// 如果提供了初始 Token，则设置它
+	if cfg.InitialToken != nil {
+		client.setToken(cfg.InitialToken)
+	}
+
+	return client
+}
+
+// buildConfig 应用配置选项并完成派生配置，配置无效时 panic，使错误的端点在启动时暴露
+func buildConfig(opts []config.Option) *config.Config {
 	cfg := config.DefaultConfig()
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if err := cfg.Validate(); err != nil {
+		panic("tencent-doc-sdk: " + err.Error())
+	}
 
-	httpClient := &http.Client{Timeout: cfg.Timeout}
+	if cfg.Metrics == nil {
+		cfg.Metrics = telemetry.NopMetrics{}
+	}
 
-	// 如果配置了自定义 Transport，则使用它
-	if cfg.Transport != nil {
-		httpClient.Transport = cfg.Transport
+	// 配置了密钥环时，令牌加密后再写入存储
+	if cfg.TokenStore != nil && cfg.TokenKeyring != nil {
+		cfg.TokenStore = store.NewEncryptedStore(cfg.TokenStore, cfg.TokenKeyring)
 	}
 
-	client := &Client{
-		config:     cfg,
-		httpClient: httpClient,
+	return cfg
+}
+
+// newExecutors 根据配置创建 OpenAPI 请求执行器和文件下载执行器
+//
+// 两者共享同一个底层 Transport、重试策略和拦截器，文件下载不占用 OpenAPI 的限流额度。
+func newExecutors(cfg *config.Config) (executor, downloader *util.Executor) {
+	base := cfg.Transport
+	if base == nil {
+		base = http.DefaultTransport
 	}
 
-	// 如果提供了初始 Token，则设置它
-	if cfg.InitialToken != nil {
-		client.token = cfg.InitialToken
+	// 日志、指标和链路追踪位于重试之内，每次重试单独记录
+	if cfg.Logger != nil {
+		base = util.NewLoggingTransport(base, cfg.Logger)
+	}
+	if _, nop := cfg.Metrics.(telemetry.NopMetrics); !nop {
+		base = util.NewMetricsTransport(base, cfg.Metrics)
+	}
+	if cfg.Tracer != nil {
+		base = util.NewTracingTransport(base, cfg.Tracer)
 	}
 
-	return client
+	apiTransport := base
+	// 限流位于重试之内，每次重试同样需要等待额度
+	if cfg.RateLimiter != nil {
+		apiTransport = util.NewRateLimitTransport(apiTransport, cfg.RateLimiter)
+	}
+
+	// 未配置 RetryPolicy 时同样安装 RetryTransport，以便通过 WithRetry 为单次调用开启重试。
+	// 超时由 instrument 通过 ctx 控制，以便单次调用覆盖 config.Timeout。
+	executor = util.NewExecutor(&http.Client{
+		Transport: util.NewRetryTransport(apiTransport, cfg.RetryPolicy),
+	}, cfg.Interceptors...)
+	downloader = util.NewExecutor(&http.Client{
+		Transport: util.NewRetryTransport(base, cfg.RetryPolicy),
+	}, cfg.Interceptors...)
+	return executor, downloader
+}
+
+// newClient 创建客户端，openID 不为空时首次调用接口会从 TokenStore 加载该用户的令牌
+func newClient(cfg *config.Config, executor, downloader *util.Executor, openID string) *Client {
+	return &Client{
+		config:     cfg,
+		executor:   executor,
+		downloader: downloader,
+		openID:     openID,
+		exports:    &exportTracker{},
+	}
+}
+
+// call 发送带鉴权请求头的 OpenAPI 请求，并将响应解析到 result
+//
+// 令牌过期时自动刷新并重试一次，req 不会被修改。
+func (c *Client) call(ctx context.Context, req *util.Request, result interface{}) error {
+	return c.withToken(ctx, func(token *model.Token) error {
+		authed := *req
+		authed.Header = req.Header.Clone()
+		if authed.Header == nil {
+			authed.Header = make(http.Header)
+		}
+		authed.Header.Set("Access-Token", token.AccessToken)
+		authed.Header.Set("Client-Id", c.config.ClientID)
+		authed.Header.Set("Open-Id", token.UserID)
+
+		return c.send(ctx, c.executor.DoAPI, &authed, result)
+	})
 }
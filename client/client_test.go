@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+func TestClientUsesConfiguredBaseURL(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		if r.URL.Path == "/oauth/v2/token" {
+			w.Write([]byte(`{"access_token":"a","refresh_token":"r","user_id":"u1","expires_in":7200}`))
+			return
+		}
+		w.Write([]byte(`{"ret":0,"msg":"ok","data":{}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(config.WithBaseURL(srv.URL + "/"))
+	ctx := context.Background()
+
+	if got := c.GetAuthURL(); !strings.HasPrefix(got, srv.URL+"/oauth/v2/authorize?") {
+		t.Fatalf("GetAuthURL() = %q", got)
+	}
+	if _, err := c.ExchangeToken(ctx, "code"); err != nil {
+		t.Fatalf("ExchangeToken() error = %v", err)
+	}
+	if _, err := c.GetUserInfo(ctx); err != nil {
+		t.Fatalf("GetUserInfo() error = %v", err)
+	}
+	if _, err := c.ListDocuments(ctx, &model.ListParams{}); err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if _, err := c.SearchDocuments(ctx, &model.SearchParams{SearchKey: "k"}); err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	if _, err := c.GetFileMetadata(ctx, "f1"); err != nil {
+		t.Fatalf("GetFileMetadata() error = %v", err)
+	}
+	if _, err := c.ExportDocument(ctx, "f1", &model.ExportRequest{}); err != nil {
+		t.Fatalf("ExportDocument() error = %v", err)
+	}
+	if _, err := c.GetExportProgress(ctx, "f1", "op1"); err != nil {
+		t.Fatalf("GetExportProgress() error = %v", err)
+	}
+
+	want := []string{
+		"/oauth/v2/token",
+		"/oauth/v2/userinfo",
+		"/openapi/drive/v2/files/f1/async-export",
+		"/openapi/drive/v2/files/f1/export-progress",
+		"/openapi/drive/v2/files/f1/metadata",
+		"/openapi/drive/v2/filter",
+		"/openapi/drive/v2/search",
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestClientRejectsMalformedEndpoint(t *testing.T) {
+	t.Parallel()
+
+	for _, opt := range []config.Option{
+		config.WithAuthEndpoint("http://[::1"),
+		config.WithTokenEndpoint("/oauth/v2/token"),
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "Endpoint") {
+					t.Errorf("NewClient() panic = %v, want invalid endpoint", r)
+				}
+			}()
+			NewClient(opt)
+		}()
+	}
+}
+
+func TestDownloadFromCOSUsesConfiguredTransport(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Disposition": []string{`attachment; filename="doc.docx"`}},
+			Body:       io.NopCloser(strings.NewReader("content")),
+		}, nil
+	})
+
+	c := NewClient(config.WithHttpTransport(transport))
+	dir := t.TempDir()
+
+	path, err := c.DownloadFromCOS(context.Background(), "https://cos.example.com/export/1", dir)
+	if err != nil {
+		t.Fatalf("DownloadFromCOS() error = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("transport calls = %d, want 1", calls)
+	}
+	if path != filepath.Join(dir, "doc.docx") {
+		t.Fatalf("path = %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "content" {
+		t.Fatalf("ReadFile() = %q, %v", data, err)
+	}
+}
+
+func TestDownloadFromCOSHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	c := NewClient(config.WithHttpTransport(transport), config.WithRetryPolicy(nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.DownloadFromCOS(ctx, "https://cos.example.com/export/1", t.TempDir())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("DownloadFromCOS() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDownloadFromCOSTimeoutCoversHeadersOnly(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Disposition": []string{`attachment; filename="doc.docx"`}},
+			Body:       &slowBody{ctxBody: ctxBody{ctx: req.Context(), r: strings.NewReader("content")}, delay: 20 * time.Millisecond},
+		}, nil
+	})
+
+	// 写入内容共耗时约80ms，超过超时时间
+	c := NewClient(config.WithHttpTransport(transport), config.WithTimeout(50*time.Millisecond))
+	path, err := c.DownloadFromCOS(context.Background(), "https://cos.example.com/export/1", t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadFromCOS() error = %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "content" {
+		t.Fatalf("ReadFile() = %q, %v", data, err)
+	}
+}
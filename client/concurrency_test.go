@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// echoTokenTransport 在 data.next 中回显请求使用的访问令牌
+func echoTokenTransport() http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(fmt.Sprintf(`{"ret":0,"msg":"ok","data":{"next":0,"list":[{"ID":%q}]}}`,
+			req.Header.Get("Access-Token"))), nil
+	})
+}
+
+func TestClientConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient(
+		config.WithHttpTransport(echoTokenTransport()),
+		config.WithInitialToken(&model.Token{AccessToken: "shared", UserID: "u0"}),
+	)
+	params := &model.ListParams{} // 多个协程共享同一份参数
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.Background()
+
+			switch i % 4 {
+			case 0:
+				if _, err := c.ListDocuments(ctx, params); err != nil {
+					t.Errorf("ListDocuments() error = %v", err)
+				}
+			case 1:
+				c.WithToken(&model.Token{AccessToken: "shared", UserID: "u0"})
+			case 2:
+				want := fmt.Sprintf("copy-%d", i)
+				got := firstDocumentID(t, ctx, c.ForToken(&model.Token{AccessToken: want}))
+				if got != want {
+					t.Errorf("ForToken() used token %q, want %q", got, want)
+				}
+			case 3:
+				want := fmt.Sprintf("ctx-%d", i)
+				got := firstDocumentID(t, ContextWithToken(ctx, &model.Token{AccessToken: want}), c)
+				if got != want {
+					t.Errorf("ContextWithToken() used token %q, want %q", got, want)
+				}
+			}
+			_ = c.Token()
+		}(i)
+	}
+	wg.Wait()
+
+	if got := c.Token().AccessToken; got != "shared" {
+		t.Fatalf("shared client token = %q, want shared", got)
+	}
+	if *params != (model.ListParams{}) {
+		t.Fatalf("ListDocuments() modified params: %+v", params)
+	}
+}
+
+func TestContextTokenIsNotRefreshed(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/oauth/v2/token" {
+			t.Error("context token must not be refreshed")
+		}
+		return jsonResponse(`{"ret":400007,"msg":"access token expired"}`), nil
+	})
+
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithInitialToken(&model.Token{AccessToken: "shared", RefreshToken: "r", UserID: "u0"}),
+	)
+	ctx := ContextWithToken(context.Background(), &model.Token{AccessToken: "override", RefreshToken: "r2"})
+
+	if _, err := c.ListDocuments(ctx, &model.ListParams{}); err == nil {
+		t.Fatal("ListDocuments() error = nil")
+	}
+	if got := c.Token().AccessToken; got != "shared" {
+		t.Fatalf("shared client token = %q, want shared", got)
+	}
+}
+
+func firstDocumentID(t *testing.T, ctx context.Context, c *Client) string {
+	t.Helper()
+
+	resp, err := c.ListDocuments(ctx, &model.ListParams{})
+	if err != nil {
+		t.Errorf("ListDocuments() error = %v", err)
+		return ""
+	}
+	if len(resp.Data.List) == 0 {
+		return ""
+	}
+	return resp.Data.List[0].ID
+}
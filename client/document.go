@@ -7,6 +7,7 @@ import (
 
 	"github.com/chinahtl/tencent-doc-sdk/constant"
 	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
 	"github.com/chinahtl/tencent-doc-sdk/util"
 )
 
@@ -22,19 +23,20 @@ import (
 //   - IsOwner: 是否仅显示自己创建的文档
 //   - FileType: 文件类型过滤（可选）
 //
+// params 不会被修改。
+//
 // 返回文档列表响应，包含文档信息列表及相关元数据。
 // 如果发生错误，可能的错误类型包括：
 //   - access token未设置
 //   - API调用失败
-//   - 服务端返回错误
+//   - 服务端返回错误（*model.APIError，可通过 errors.Is 判断 model.ErrNotFound 等类型）
 //
 // API参考：https://docs.qq.com/open/document/app/openapi/v2/file/filter/filter.html
-func (c *Client) ListDocuments(ctx context.Context, params *model.ListParams) (*model.ListDocumentsResponse, error) {
-	if c.token == nil || c.token.AccessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
+func (c *Client) ListDocuments(ctx context.Context, params *model.ListParams, opts ...CallOption) (*model.ListDocumentsResponse, error) {
+	// 在副本上设置默认值，不修改调用方传入的参数，便于多个协程共享同一份参数
+	p := *params
+	params = &p
 
-	// 设置默认值
 	if params.ListType == "" {
 		params.ListType = constant.ListTypeFolder
 	}
@@ -48,15 +50,8 @@ func (c *Client) ListDocuments(ctx context.Context, params *model.ListParams) (*
 		params.FolderID = "/" // 默认根目录
 	}
 
-	// 构建请求URL
-	endpoint := fmt.Sprintf("%s/drive/v2/filter", constant.APIEndpoint)
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("parse endpoint failed: %w", err)
-	}
-
 	// 添加查询参数
-	q := u.Query()
+	q := url.Values{}
 	q.Add("listType", params.ListType)
 	q.Add("sortType", params.SortType)
 	q.Add("asc", fmt.Sprintf("%d", params.Asc))
@@ -69,29 +64,19 @@ func (c *Client) ListDocuments(ctx context.Context, params *model.ListParams) (*
 		q.Add("fileType", params.FileType)
 	}
 
-	u.RawQuery = q.Encode()
-
 	// 发送请求
 	var result model.ListDocumentsResponse
-	err = util.GetWithCustomHeaders(
-		ctx,
-		c.httpClient,
-		u.String(),
-		map[string]string{
-			"Access-Token": c.token.AccessToken,
-			"Client-Id":    c.config.ClientID,
-			"Open-Id":      c.token.UserID,
-		},
-		&result,
-	)
+	attrs := []telemetry.Attribute{telemetry.String(telemetry.AttrFolderID, params.FolderID)}
+	err := c.instrument(ctx, "ListDocuments", attrs, opts, func(ctx context.Context) error {
+		return c.call(ctx, &util.Request{
+			Endpoint: fmt.Sprintf("%s/drive/v2/filter", c.config.APIEndpoint),
+			Query:    q,
+		}, &result)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("list documents failed: %w", err)
 	}
 
-	if result.Ret != 0 {
-		return nil, fmt.Errorf("api error: %s (ret=%d)", result.Msg, result.Ret)
-	}
-
 	return &result, nil
 }
 
@@ -112,23 +97,16 @@ func (c *Client) ListDocuments(ctx context.Context, params *model.ListParams) (*
 // 如果发生错误，可能的错误类型包括：
 //   - access token未设置
 //   - API调用失败
-//   - 服务端返回错误
+//   - 服务端返回错误（*model.APIError，可通过 errors.Is 判断 model.ErrNotFound 等类型）
 //
 // API参考：https://docs.qq.com/open/document/app/openapi/v2/file/search/search.html
-func (c *Client) SearchDocuments(ctx context.Context, params *model.SearchParams) (*model.SearchDocumentsResponse, error) {
-	if c.token == nil || c.token.AccessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
-
-	// 构建请求URL
-	endpoint := fmt.Sprintf("%s/drive/v2/search", constant.APIEndpoint)
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("parse endpoint failed: %w", err)
-	}
-
+func (c *Client) SearchDocuments(
+	ctx context.Context,
+	params *model.SearchParams,
+	opts ...CallOption,
+) (*model.SearchDocumentsResponse, error) {
 	// 添加查询参数
-	q := u.Query()
+	q := url.Values{}
 	q.Add("searchType", params.SearchType)
 	q.Add("searchKey", params.SearchKey)
 	q.Add("folderID", params.FolderID)
@@ -145,29 +123,19 @@ func (c *Client) SearchDocuments(ctx context.Context, params *model.SearchParams
 		q.Add("asc", fmt.Sprintf("%d", params.Asc))
 	}
 
-	u.RawQuery = q.Encode()
-
 	// 发送请求
 	var result model.SearchDocumentsResponse
-	err = util.GetWithCustomHeaders(
-		ctx,
-		c.httpClient,
-		u.String(),
-		map[string]string{
-			"Access-Token": c.token.AccessToken,
-			"Client-Id":    c.config.ClientID,
-			"Open-Id":      c.token.UserID,
-		},
-		&result,
-	)
+	attrs := []telemetry.Attribute{telemetry.String(telemetry.AttrFolderID, params.FolderID)}
+	err := c.instrument(ctx, "SearchDocuments", attrs, opts, func(ctx context.Context) error {
+		return c.call(ctx, &util.Request{
+			Endpoint: fmt.Sprintf("%s/drive/v2/search", c.config.APIEndpoint),
+			Query:    q,
+		}, &result)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("search documents failed: %w", err)
 	}
 
-	if result.Ret != 0 {
-		return nil, fmt.Errorf("api error: %s (ret=%d)", result.Msg, result.Ret)
-	}
-
 	return &result, nil
 }
 
@@ -186,41 +154,21 @@ func (c *Client) SearchDocuments(ctx context.Context, params *model.SearchParams
 //   - access token未设置
 //   - 文件ID无效
 //   - API调用失败
-//   - 服务端返回错误
+//   - 服务端返回错误（*model.APIError，可通过 errors.Is 判断 model.ErrNotFound 等类型）
 //
 // API参考：https://docs.qq.com/open/document/app/openapi/v2/file/files/metadata.html
-func (c *Client) GetFileMetadata(ctx context.Context, fileID string) (*model.FileMetadataResponse, error) {
-	if c.token == nil || c.token.AccessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
-
-	// 构建请求URL
-	endpoint := fmt.Sprintf("%s/drive/v2/files/%s/metadata", constant.APIEndpoint, fileID)
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("parse endpoint failed: %w", err)
-	}
-
+func (c *Client) GetFileMetadata(ctx context.Context, fileID string, opts ...CallOption) (*model.FileMetadataResponse, error) {
 	// 发送请求
 	var result model.FileMetadataResponse
-	err = util.GetWithCustomHeaders(
-		ctx,
-		c.httpClient,
-		u.String(),
-		map[string]string{
-			"Access-Token": c.token.AccessToken,
-			"Client-Id":    c.config.ClientID,
-			"Open-Id":      c.token.UserID,
-		},
-		&result,
-	)
+	attrs := []telemetry.Attribute{telemetry.String(telemetry.AttrFileID, fileID)}
+	err := c.instrument(ctx, "GetFileMetadata", attrs, opts, func(ctx context.Context) error {
+		return c.call(ctx, &util.Request{
+			Endpoint: fmt.Sprintf("%s/drive/v2/files/%s/metadata", c.config.APIEndpoint, url.PathEscape(fileID)),
+		}, &result)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("get file metadata failed: %w", err)
 	}
 
-	if result.Ret != 0 {
-		return nil, fmt.Errorf("api error: %s (ret=%d)", result.Msg, result.Ret)
-	}
-
 	return &result, nil
 }
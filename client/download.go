@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+// DownloadFromCOS 下载导出完成的文件到本地目录 saveDir，返回文件的完整本地路径。
+//
+// ctx 用于控制请求的上下文，可用于超时控制和取消。
+//
+// fileURL 通常来自 GetExportProgress 返回的下载地址；saveDir 为空时保存到当前工作目录。
+//
+// 下载请求使用客户端配置的 Transport、超时、重试策略和拦截器，但不占用 OpenAPI 的限流额度。
+// 文件名从响应头 Content-Disposition 中解析。超时时间（config.Timeout 或 WithCallTimeout）只限制到收到响应头为止，
+// 写入文件的时间不受限制，可通过 ctx 取消。
+//
+// opts 中仅 WithCallTimeout、WithRetry 和 WithoutRetry 对下载生效。
+func (c *Client) DownloadFromCOS(ctx context.Context, fileURL, saveDir string, opts ...CallOption) (string, error) {
+	ctx, received, cancel := c.withHeaderTimeout(ctx, opts)
+	defer cancel()
+
+	var path string
+	err := c.trace(ctx, "DownloadFromCOS", nil, func(ctx context.Context) error {
+		resp, err := c.downloader.Stream(ctx, &util.Request{Endpoint: fileURL})
+		if err != nil {
+			return fmt.Errorf("下载请求失败: %w", err)
+		}
+		if err := received(); err != nil {
+			resp.Body.Close()
+			return fmt.Errorf("下载请求失败: %w", err)
+		}
+		path, err = util.SaveResponse(resp, saveDir)
+		return err
+	})
+	if err == nil {
+		if info, statErr := os.Stat(path); statErr == nil {
+			c.config.Metrics.AddDownloadedBytes(info.Size())
+		}
+	}
+	return path, err
+}
+
+// ExportFile 导出文件的信息
+type ExportFile struct {
+	OperationID string // 导出任务ID
+	FileName    string // 文件名，如 "周报.pdf"
+	ContentType string // 文件的 MIME 类型，如 "application/pdf"
+	Size        int64  // OpenExport 返回时为响应的 Content-Length（未知时为 -1），ExportTo 返回时为写入的字节数
+}
+
+// ExportReader 导出文件的内容流，读取完毕后必须调用 Close
+type ExportReader struct {
+	io.ReadCloser
+	ExportFile
+}
+
+// OpenExport 导出文档并返回文件内容流，不写入本地磁盘。
+//
+// 依次执行 ExportAndWait 和下载请求，opts 的含义同 ExportAndWait；下载使用 WithExportCallOptions 中的调用选项，
+// 超时时间（config.Timeout 或 WithCallTimeout）只限制到收到响应头为止，读取内容的时间不受限制，
+// 可通过 ctx 取消读取，下载请求在 Close 时结束。
+// 适用于将文件转发给浏览器等场景，可先根据 FileName 和 ContentType 设置响应头再复制内容：
+//
+//	r, err := docClient.OpenExport(ctx, docID, &model.ExportRequest{ExportType: "pdf"})
+//	if err != nil {
+//	    return err
+//	}
+//	defer r.Close()
+//	w.Header().Set("Content-Type", r.ContentType)
+//	io.Copy(w, r)
+//
+// 导出失败时返回 *ExportError，下载失败时返回下载请求的错误。
+func (c *Client) OpenExport(
+	ctx context.Context,
+	docID string,
+	req *model.ExportRequest,
+	opts ...ExportOption,
+) (*ExportReader, error) {
+	result, err := c.ExportAndWait(ctx, docID, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 下载请求的 ctx 需要在读取完内容后才能结束，不能使用 instrument
+	ctx, received, cancel := c.withHeaderTimeout(ctx, newExportOptions(opts).callOpts)
+	var download *util.Download
+	attrs := []telemetry.Attribute{
+		telemetry.String(telemetry.AttrFileID, docID),
+		telemetry.String(telemetry.AttrOperationID, result.OperationID),
+	}
+	err = c.trace(ctx, "OpenExport", attrs, func(ctx context.Context) error {
+		var err error
+		download, err = util.OpenDownload(ctx, c.downloader, result.URL)
+		if err != nil {
+			return err
+		}
+		if err := received(); err != nil {
+			download.Body.Close()
+			return fmt.Errorf("下载请求失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &ExportReader{
+		ReadCloser: &exportBody{body: download.Body, cancel: cancel, metrics: c.config.Metrics},
+		ExportFile: ExportFile{
+			OperationID: result.OperationID,
+			FileName:    download.FileName,
+			ContentType: download.ContentType,
+			Size:        download.Size,
+		},
+	}, nil
+}
+
+// ExportTo 导出文档并将文件内容写入 w，不写入本地磁盘，返回的 ExportFile.Size 为写入的字节数。
+//
+// 执行过程和 opts 的含义同 OpenExport。w 为 http.ResponseWriter 且需要根据文件名设置响应头时，应使用 OpenExport。
+func (c *Client) ExportTo(
+	ctx context.Context,
+	w io.Writer,
+	docID string,
+	req *model.ExportRequest,
+	opts ...ExportOption,
+) (*ExportFile, error) {
+	r, err := c.OpenExport(ctx, docID, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return nil, fmt.Errorf("copy export content failed: %w", err)
+	}
+	file := r.ExportFile
+	file.Size = n
+	return &file, nil
+}
+
+// exportBody 在关闭时结束下载请求的 ctx 并统计下载字节数
+type exportBody struct {
+	body    io.ReadCloser
+	cancel  func()
+	metrics telemetry.Metrics
+	n       int64
+	closed  bool
+}
+
+func (b *exportBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *exportBody) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	err := b.body.Close()
+	b.cancel()
+	b.metrics.AddDownloadedBytes(b.n)
+	return err
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// ErrPaginationStalled 分页迭代时服务端返回的下一页位置没有前进，继续请求会无限循环
+var ErrPaginationStalled = errors.New("pagination cursor did not advance")
+
+// ExportAndWait 失败的原因，可通过 errors.Is 判断
+var (
+	ErrExportTimeout  = errors.New("export timed out")
+	ErrExportCanceled = errors.New("export canceled")
+	ErrExportFailed   = errors.New("export failed")
+)
+
+// ExportError ExportAndWait 失败时返回的错误
+//
+// errors.Is 可同时匹配失败原因（ErrExportTimeout、ErrExportCanceled、ErrExportFailed）和底层错误，
+// 如 context.DeadlineExceeded 或 model.ErrNotFound；errors.As 可获取底层的 *model.APIError。
+type ExportError struct {
+	Reason      error  // ErrExportTimeout、ErrExportCanceled 或 ErrExportFailed
+	DocID       string // 文档ID
+	OperationID string // 导出任务ID，创建任务失败时为空
+	Progress    int    // 最后一次查询到的进度
+	Err         error  // 底层错误
+}
+
+// Error 实现 error 接口
+func (e *ExportError) Error() string {
+	msg := fmt.Sprintf("%v (doc=%s", e.Reason, e.DocID)
+	if e.OperationID != "" {
+		msg += fmt.Sprintf(", operation=%s, progress=%d%%", e.OperationID, e.Progress)
+	}
+	return msg + "): " + e.Err.Error()
+}
+
+// Unwrap 返回失败原因和底层错误
+func (e *ExportError) Unwrap() []error {
+	return []error{e.Reason, e.Err}
+}
+
+// newExportError 根据 ctx 的状态判断失败原因：ctx 超时为超时，ctx 被取消为取消，否则为导出失败
+func newExportError(ctx context.Context, docID, operationID string, progress int, err error) *ExportError {
+	reason := ErrExportFailed
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		reason = ErrExportTimeout
+	case ctx.Err() != nil:
+		reason = ErrExportCanceled
+	}
+	return &ExportError{Reason: reason, DocID: docID, OperationID: operationID, Progress: progress, Err: err}
+}
+
+// isTokenError 判断错误是否由访问令牌过期或失效导致
+func isTokenError(err error) bool {
+	return errors.Is(err, model.ErrTokenExpired) || errors.Is(err, model.ErrInvalidToken)
+}
+
+// isTransientError 判断错误是否可能在稍后重试时恢复：网络错误、HTTP 5xx 和限流
+func isTransientError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	var apiErr *model.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || errors.Is(apiErr, model.ErrRateLimited)
+	}
+	return false
+}
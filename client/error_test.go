@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+func TestClientReturnsAPIError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{name: "ret code", status: http.StatusOK, body: `{"ret":400004,"msg":"file not found"}`, want: model.ErrNotFound},
+		{name: "http status", status: http.StatusForbidden, body: `forbidden`, want: model.ErrPermissionDenied},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{"ret":400009,"msg":"too many requests"}`, want: model.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: tt.status,
+					Header:     http.Header{"X-Request-Id": []string{"req-1"}},
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+				}, nil
+			})
+			c := NewClient(
+				config.WithHttpTransport(transport),
+				config.WithInitialToken(&model.Token{AccessToken: "a"}),
+			)
+
+			_, err := c.GetFileMetadata(context.Background(), "file1")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetFileMetadata() error = %v, want %v", err, tt.want)
+			}
+
+			var apiErr *model.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetFileMetadata() error = %T, want *model.APIError", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Fatalf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if !strings.HasSuffix(apiErr.Endpoint, "/drive/v2/files/file1/metadata") {
+				t.Fatalf("Endpoint = %q", apiErr.Endpoint)
+			}
+			if tt.status != http.StatusOK && apiErr.RequestID != "req-1" {
+				t.Fatalf("RequestID = %q, want %q", apiErr.RequestID, "req-1")
+			}
+		})
+	}
+}
@@ -3,10 +3,12 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
+	"time"
 
-	"github.com/chinahtl/tencent-doc-sdk/constant"
 	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
 	"github.com/chinahtl/tencent-doc-sdk/util"
 )
 
@@ -27,20 +29,19 @@ import (
 //   - access token未设置
 //   - 文档ID为空
 //   - API调用失败
-//   - 服务端返回错误
+//   - 服务端返回错误（*model.APIError，可通过 errors.Is 判断 model.ErrNotFound 等类型）
 //
 // API参考：https://docs.qq.com/open/document/app/openapi/v2/file/export/async_export.html
-func (c *Client) ExportDocument(ctx context.Context, docID string, req *model.ExportRequest) (*model.ExportResponse, error) {
-	if c.token == nil || c.token.AccessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
+func (c *Client) ExportDocument(
+	ctx context.Context,
+	docID string,
+	req *model.ExportRequest,
+	opts ...CallOption,
+) (*model.ExportResponse, error) {
 	if docID == "" {
 		return nil, fmt.Errorf("document ID cannot be empty")
 	}
 
-	// 构建请求URL
-	endpoint := fmt.Sprintf("%s/drive/v2/files/%s/async-export", constant.APIEndpoint, docID)
-
 	// 准备表单数据
 	form := url.Values{}
 	if req.ExportType != "" {
@@ -49,26 +50,21 @@ func (c *Client) ExportDocument(ctx context.Context, docID string, req *model.Ex
 
 	// 发送请求
 	var result model.ExportResponse
-	err := util.PostFormWithHeaders(
-		ctx,
-		c.httpClient,
-		endpoint,
-		form,
-		map[string]string{
-			"Access-Token": c.token.AccessToken,
-			"Client-Id":    c.config.ClientID,
-			"Open-Id":      c.token.UserID,
-			"Content-Type": "application/x-www-form-urlencoded",
-		},
-		&result,
-	)
+	attrs := []telemetry.Attribute{
+		telemetry.String(telemetry.AttrFileID, docID),
+		telemetry.String(telemetry.AttrExportType, req.ExportType),
+	}
+	err := c.instrument(ctx, "ExportDocument", attrs, opts, func(ctx context.Context) error {
+		return c.call(ctx, &util.Request{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("%s/drive/v2/files/%s/async-export", c.config.APIEndpoint, url.PathEscape(docID)),
+			Form:     form,
+		}, &result)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("export document failed: %w", err)
 	}
-
-	if result.Ret != 0 {
-		return nil, fmt.Errorf("api error: %s (ret=%d)", result.Msg, result.Ret)
-	}
+	c.exports.start(result.Data.OperationID, req.ExportType, time.Now())
 
 	return &result, nil
 }
@@ -92,17 +88,15 @@ func (c *Client) ExportDocument(ctx context.Context, docID string, req *model.Ex
 //   - 文档ID为空
 //   - 操作ID为空
 //   - API调用失败
-//   - 服务端返回错误
+//   - 服务端返回错误（*model.APIError，可通过 errors.Is 判断 model.ErrNotFound 等类型）
 //
 // API参考：https://docs.qq.com/open/document/app/openapi/v2/file/export/export_progress.html
 func (c *Client) GetExportProgress(
 	ctx context.Context,
 	docID string,
 	operationID string,
+	opts ...CallOption,
 ) (*model.ExportProgressResponse, error) {
-	if c.token == nil || c.token.AccessToken == "" {
-		return nil, fmt.Errorf("access token is required")
-	}
 	if docID == "" {
 		return nil, fmt.Errorf("document ID cannot be empty")
 	}
@@ -110,37 +104,25 @@ func (c *Client) GetExportProgress(
 		return nil, fmt.Errorf("operation ID cannot be empty")
 	}
 
-	// 构建请求URL
-	endpoint := fmt.Sprintf("%s/drive/v2/files/%s/export-progress", constant.APIEndpoint, docID)
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("parse endpoint failed: %w", err)
-	}
-
-	// 添加查询参数
-	q := u.Query()
-	q.Add("operationID", operationID)
-	u.RawQuery = q.Encode()
-
 	// 发送请求
 	var result model.ExportProgressResponse
-	err = util.GetWithCustomHeaders(
-		ctx,
-		c.httpClient,
-		u.String(),
-		map[string]string{
-			"Access-Token": c.token.AccessToken,
-			"Client-Id":    c.config.ClientID,
-			"Open-Id":      c.token.UserID,
-		},
-		&result,
-	)
+	attrs := []telemetry.Attribute{
+		telemetry.String(telemetry.AttrFileID, docID),
+		telemetry.String(telemetry.AttrOperationID, operationID),
+	}
+	err := c.instrument(ctx, "GetExportProgress", attrs, opts, func(ctx context.Context) error {
+		return c.call(ctx, &util.Request{
+			Endpoint: fmt.Sprintf("%s/drive/v2/files/%s/export-progress", c.config.APIEndpoint, url.PathEscape(docID)),
+			Query:    url.Values{"operationID": []string{operationID}},
+		}, &result)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("get export progress failed: %w", err)
 	}
-
-	if result.Ret != 0 {
-		return nil, fmt.Errorf("api error: %s (ret=%d)", result.Msg, result.Ret)
+	if result.Data.Progress == 100 {
+		if exportType, duration, ok := c.exports.finish(operationID, time.Now()); ok {
+			c.config.Metrics.ObserveExport(exportType, duration)
+		}
 	}
 
 	return &result, nil
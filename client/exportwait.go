@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+// ExportOption ExportAndWait 配置选项
+type ExportOption func(*exportOptions)
+
+type exportOptions struct {
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	multiplier      float64
+	timeout         time.Duration
+	progress        func(progress int)
+	callOpts        []CallOption
+}
+
+func newExportOptions(opts []ExportOption) *exportOptions {
+	o := &exportOptions{
+		initialInterval: time.Second,
+		maxInterval:     10 * time.Second,
+		multiplier:      1.5,
+		timeout:         10 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// interval 返回第 poll 次查询进度前（从1开始）的等待时间
+func (o *exportOptions) interval(poll int) time.Duration {
+	d := float64(o.initialInterval) * math.Pow(max(o.multiplier, 1), float64(poll-1))
+	if o.maxInterval > 0 && d > float64(o.maxInterval) {
+		return o.maxInterval
+	}
+	return time.Duration(d)
+}
+
+// WithPollInterval 设置查询进度的间隔，从 initial 开始按倍数增长，不超过 maxInterval，默认从1s增长到10s
+func WithPollInterval(initial, maxInterval time.Duration) ExportOption {
+	return func(o *exportOptions) {
+		o.initialInterval = initial
+		o.maxInterval = maxInterval
+	}
+}
+
+// WithPollMultiplier 设置查询间隔的增长倍数，默认1.5，小于等于1时间隔固定
+func WithPollMultiplier(multiplier float64) ExportOption {
+	return func(o *exportOptions) {
+		o.multiplier = multiplier
+	}
+}
+
+// WithExportTimeout 设置从创建导出任务到完成的总超时时间，默认10分钟，0 表示只受 ctx 限制
+func WithExportTimeout(timeout time.Duration) ExportOption {
+	return func(o *exportOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithProgressFunc 设置进度回调，每次查询到进度后调用
+func WithProgressFunc(fn func(progress int)) ExportOption {
+	return func(o *exportOptions) {
+		o.progress = fn
+	}
+}
+
+// WithExportCallOptions 设置创建导出任务和每次查询进度时使用的调用选项
+func WithExportCallOptions(opts ...CallOption) ExportOption {
+	return func(o *exportOptions) {
+		o.callOpts = append(o.callOpts, opts...)
+	}
+}
+
+// ExportResult 导出完成的结果
+type ExportResult struct {
+	OperationID string        // 导出任务ID
+	URL         string        // 导出文件的下载地址，可传给 DownloadFromCOS
+	Polls       int           // 查询进度的次数
+	Duration    time.Duration // 从创建任务到完成的耗时
+}
+
+// ExportAndWait 创建导出任务并等待导出完成，返回下载地址。
+//
+// 创建任务后按退避间隔轮询 GetExportProgress，直到进度达到100，可通过 ExportOption 配置间隔、
+// 总超时时间和进度回调。配置了 Tracer 时整个轮询过程记录为一个 "ExportAndWait" span，
+// 创建任务和每次查询进度为其子 span。
+//
+// 失败时返回 *ExportError，可通过 errors.Is 判断原因：
+//   - ErrExportTimeout: 超过 WithExportTimeout 设置的时间或 ctx 超时
+//   - ErrExportCanceled: ctx 被取消
+//   - ErrExportFailed: 创建任务失败、查询进度返回不可重试的错误，或导出完成但没有返回下载地址
+//
+// 查询进度遇到网络错误、HTTP 5xx 或限流时继续轮询，直到超时。创建任务后的错误均带有 ExportError.OperationID，
+// 导出任务可能仍在服务端执行，可通过 GetExportProgress 继续查询。
+func (c *Client) ExportAndWait(
+	ctx context.Context,
+	docID string,
+	req *model.ExportRequest,
+	opts ...ExportOption,
+) (*ExportResult, error) {
+	o := newExportOptions(opts)
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	var result *ExportResult
+	attrs := []telemetry.Attribute{
+		telemetry.String(telemetry.AttrFileID, docID),
+		telemetry.String(telemetry.AttrExportType, req.ExportType),
+	}
+	err := c.trace(ctx, "ExportAndWait", attrs, func(ctx context.Context) error {
+		var err error
+		result, err = c.exportAndWait(ctx, docID, req, o)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) exportAndWait(ctx context.Context, docID string, req *model.ExportRequest, o *exportOptions) (*ExportResult, error) {
+	start := time.Now()
+	span := telemetry.SpanFromContext(ctx)
+
+	resp, err := c.ExportDocument(ctx, docID, req, o.callOpts...)
+	if err != nil {
+		return nil, newExportError(ctx, docID, "", 0, err)
+	}
+	operationID := resp.Data.OperationID
+	if span != nil {
+		span.SetAttributes(telemetry.String(telemetry.AttrOperationID, operationID))
+	}
+
+	timer := time.NewTimer(o.interval(1))
+	defer timer.Stop()
+
+	progress := 0
+	var lastErr error // 最近一次查询进度的临时错误
+	for poll := 1; ; poll++ {
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if lastErr != nil {
+				err = fmt.Errorf("%w (last poll error: %w)", err, lastErr)
+			}
+			return nil, newExportError(ctx, docID, operationID, progress, err)
+		case <-timer.C:
+		}
+
+		resp, err := c.GetExportProgress(ctx, docID, operationID, o.callOpts...)
+		if span != nil {
+			span.SetAttributes(telemetry.Int(telemetry.AttrPolls, poll))
+		}
+		if err != nil {
+			// 导出任务仍在服务端执行，网络错误等临时错误时继续轮询直到 ctx 结束
+			if ctx.Err() == nil && isTransientError(err) {
+				lastErr = err
+				timer.Reset(o.interval(poll + 1))
+				continue
+			}
+			return nil, newExportError(ctx, docID, operationID, progress, err)
+		}
+		lastErr = nil
+
+		progress = resp.Data.Progress
+		if o.progress != nil {
+			o.progress(progress)
+		}
+		if progress >= 100 {
+			if resp.Data.URL == "" {
+				return nil, newExportError(ctx, docID, operationID, progress, fmt.Errorf("export completed without download URL"))
+			}
+			return &ExportResult{
+				OperationID: operationID,
+				URL:         resp.Data.URL,
+				Polls:       poll,
+				Duration:    time.Since(start),
+			}, nil
+		}
+		timer.Reset(o.interval(poll + 1))
+	}
+}
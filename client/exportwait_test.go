@@ -0,0 +1,317 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+// exportTransport 模拟导出接口，progress 返回第 n 次查询（从1开始）的响应体
+func exportTransport(progress func(n int) string) http.RoundTripper {
+	var polls atomic.Int32
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/async-export") {
+			return jsonResponse(`{"ret":0,"msg":"ok","data":{"operationID":"op1"}}`), nil
+		}
+		return jsonResponse(progress(int(polls.Add(1)))), nil
+	})
+}
+
+func newExportClient(transport http.RoundTripper, opts ...config.Option) *Client {
+	opts = append(opts,
+		config.WithHttpTransport(transport),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+	return NewClient(opts...)
+}
+
+func TestExportAndWait(t *testing.T) {
+	t.Parallel()
+
+	transport := exportTransport(func(n int) string {
+		if n < 3 {
+			return fmt.Sprintf(`{"ret":0,"msg":"ok","data":{"progress":%d}}`, n*40)
+		}
+		return `{"ret":0,"msg":"ok","data":{"progress":100,"url":"https://cos.example.com/f1.pdf"}}`
+	})
+	tracer := &recordingTracer{}
+	c := newExportClient(transport, config.WithTracer(tracer))
+
+	var progress []int
+	result, err := c.ExportAndWait(context.Background(), "f1", &model.ExportRequest{ExportType: "pdf"},
+		WithPollInterval(time.Millisecond, 5*time.Millisecond),
+		WithProgressFunc(func(p int) { progress = append(progress, p) }),
+	)
+	if err != nil {
+		t.Fatalf("ExportAndWait() error = %v", err)
+	}
+	if result.URL != "https://cos.example.com/f1.pdf" || result.OperationID != "op1" || result.Polls != 3 {
+		t.Fatalf("ExportAndWait() = %+v", result)
+	}
+	if !slices.Equal(progress, []int{40, 80, 100}) {
+		t.Fatalf("progress = %v", progress)
+	}
+
+	// 轮询过程为一个 span，创建任务和每次查询进度为独立的 span
+	if len(tracer.spans) != 5 || tracer.spans[0].operation != "ExportAndWait" {
+		t.Fatalf("spans = %d, first %q", len(tracer.spans), tracer.spans[0].operation)
+	}
+	attrs := tracer.spans[0].attrs
+	if attrs[telemetry.AttrPolls] != 3 || attrs[telemetry.AttrOperationID] != "op1" || attrs[telemetry.AttrRet] != 0 {
+		t.Fatalf("ExportAndWait span attributes = %v", attrs)
+	}
+}
+
+func TestExportAndWaitErrors(t *testing.T) {
+	t.Parallel()
+
+	stuck := func(int) string { return `{"ret":0,"msg":"ok","data":{"progress":10}}` }
+	tests := []struct {
+		name     string
+		progress func(int) string
+		opts     []ExportOption
+		cancel   bool
+		reason   error
+		cause    error
+	}{
+		{
+			name:     "timeout",
+			progress: stuck,
+			opts:     []ExportOption{WithExportTimeout(30 * time.Millisecond)},
+			reason:   ErrExportTimeout,
+			cause:    context.DeadlineExceeded,
+		},
+		{
+			name:     "canceled",
+			progress: stuck,
+			cancel:   true,
+			reason:   ErrExportCanceled,
+			cause:    context.Canceled,
+		},
+		{
+			name:     "failed",
+			progress: func(int) string { return `{"ret":400004,"msg":"operation not found"}` },
+			reason:   ErrExportFailed,
+			cause:    model.ErrNotFound,
+		},
+		{
+			name:     "no url",
+			progress: func(int) string { return `{"ret":0,"msg":"ok","data":{"progress":100}}` },
+			reason:   ErrExportFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newExportClient(exportTransport(tt.progress))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			opts := append([]ExportOption{WithPollInterval(time.Millisecond, time.Millisecond)}, tt.opts...)
+			if tt.cancel {
+				opts = append(opts, WithProgressFunc(func(int) { cancel() }))
+			}
+			_, err := c.ExportAndWait(ctx, "f1", &model.ExportRequest{}, opts...)
+
+			if !errors.Is(err, tt.reason) {
+				t.Fatalf("ExportAndWait() error = %v, want %v", err, tt.reason)
+			}
+			if tt.cause != nil && !errors.Is(err, tt.cause) {
+				t.Fatalf("ExportAndWait() error = %v, want cause %v", err, tt.cause)
+			}
+			var exportErr *ExportError
+			if !errors.As(err, &exportErr) || exportErr.OperationID != "op1" || exportErr.DocID != "f1" {
+				t.Fatalf("ExportError = %+v", exportErr)
+			}
+		})
+	}
+}
+
+func TestExportAndWaitPollsThroughTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	// flaky 返回第 n 次查询的响应，前两次分别为网络错误和 502
+	flaky := func(done bool) http.RoundTripper {
+		var polls atomic.Int32
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "/async-export") {
+				return jsonResponse(`{"ret":0,"msg":"ok","data":{"operationID":"op1"}}`), nil
+			}
+			switch n := polls.Add(1); {
+			case n == 1 || !done:
+				return nil, errors.New("connection reset")
+			case n == 2:
+				resp := jsonResponse(`bad gateway`)
+				resp.StatusCode = http.StatusBadGateway
+				return resp, nil
+			}
+			return jsonResponse(`{"ret":0,"msg":"ok","data":{"progress":100,"url":"https://cos.example.com/f1.pdf"}}`), nil
+		})
+	}
+	opts := []ExportOption{WithPollInterval(time.Millisecond, time.Millisecond), WithExportTimeout(50 * time.Millisecond)}
+
+	result, err := newExportClient(flaky(true)).ExportAndWait(context.Background(), "f1", &model.ExportRequest{}, opts...)
+	if err != nil {
+		t.Fatalf("ExportAndWait() error = %v", err)
+	}
+	if result.Polls != 3 {
+		t.Fatalf("ExportAndWait() polls = %d, want 3", result.Polls)
+	}
+
+	// 一直失败时在超时后返回，保留导出任务ID和最后一次错误
+	_, err = newExportClient(flaky(false)).ExportAndWait(context.Background(), "f1", &model.ExportRequest{}, opts...)
+	var exportErr *ExportError
+	if !errors.Is(err, ErrExportTimeout) || !errors.As(err, &exportErr) || exportErr.OperationID != "op1" {
+		t.Fatalf("ExportAndWait() error = %v, want ErrExportTimeout with operation", err)
+	}
+	if !strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("ExportAndWait() error = %v, want last poll error", err)
+	}
+}
+
+// ctxBody 读取时返回请求 ctx 的错误，用于检查下载请求的 ctx 是否在读取完之前被结束
+type ctxBody struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (b *ctxBody) Read(p []byte) (int, error) {
+	if err := b.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return b.r.Read(p)
+}
+
+func (b *ctxBody) Close() error { return nil }
+
+func streamTransport(downloadCtx *context.Context) http.RoundTripper {
+	api := exportTransport(func(int) string {
+		return `{"ret":0,"msg":"ok","data":{"progress":100,"url":"https://cos.example.com/export/op1"}}`
+	})
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != "cos.example.com" {
+			return api.RoundTrip(req)
+		}
+		*downloadCtx = req.Context()
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Disposition": []string{`attachment; filename="report.pdf"`}},
+			Body:          &ctxBody{ctx: req.Context(), r: strings.NewReader("%PDF-1.7")},
+			ContentLength: 8,
+		}, nil
+	})
+}
+
+func TestOpenExportStreamsAfterReturn(t *testing.T) {
+	t.Parallel()
+
+	var downloadCtx context.Context
+	metrics := &recordingMetrics{}
+	c := newExportClient(streamTransport(&downloadCtx), config.WithMetrics(metrics))
+
+	r, err := c.OpenExport(context.Background(), "f1", &model.ExportRequest{ExportType: "pdf"},
+		WithPollInterval(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("OpenExport() error = %v", err)
+	}
+	if r.FileName != "report.pdf" || r.ContentType != "application/pdf" || r.Size != 8 || r.OperationID != "op1" {
+		t.Fatalf("OpenExport() = %+v", r.ExportFile)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != "%PDF-1.7" {
+		t.Fatalf("ReadAll() = %q, %v", data, err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if downloadCtx.Err() == nil {
+		t.Fatal("download context not released after Close")
+	}
+	if metrics.bytes != 8 {
+		t.Fatalf("downloaded bytes = %d, want 8", metrics.bytes)
+	}
+}
+
+// slowBody 每次读取前等待 delay，读取时返回请求 ctx 的错误
+type slowBody struct {
+	ctxBody
+	delay time.Duration
+}
+
+func (b *slowBody) Read(p []byte) (int, error) {
+	time.Sleep(b.delay)
+	return b.ctxBody.Read(p[:min(len(p), 2)])
+}
+
+func TestOpenExportTimeoutCoversHeadersOnly(t *testing.T) {
+	t.Parallel()
+
+	api := exportTransport(func(int) string {
+		return `{"ret":0,"msg":"ok","data":{"progress":100,"url":"https://cos.example.com/export/op1"}}`
+	})
+	var headerDelay time.Duration
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != "cos.example.com" {
+			return api.RoundTrip(req)
+		}
+		select {
+		case <-time.After(headerDelay):
+		case <-req.Context().Done():
+			return nil, context.Cause(req.Context())
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       &slowBody{ctxBody: ctxBody{ctx: req.Context(), r: strings.NewReader("%PDF-1.7")}, delay: 20 * time.Millisecond},
+		}, nil
+	})
+	c := newExportClient(transport, config.WithTimeout(50*time.Millisecond))
+	opts := []ExportOption{WithPollInterval(time.Millisecond, time.Millisecond), WithExportCallOptions(WithoutRetry())}
+
+	// 读取内容共耗时约80ms，超过超时时间
+	r, err := c.OpenExport(context.Background(), "f1", &model.ExportRequest{ExportType: "pdf"}, opts...)
+	if err != nil {
+		t.Fatalf("OpenExport() error = %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != "%PDF-1.7" {
+		t.Fatalf("ReadAll() = %q, %v", data, err)
+	}
+
+	headerDelay = time.Second
+	if _, err := c.OpenExport(context.Background(), "f1", &model.ExportRequest{ExportType: "pdf"}, opts...); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("OpenExport() error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestExportTo(t *testing.T) {
+	t.Parallel()
+
+	var downloadCtx context.Context
+	c := newExportClient(streamTransport(&downloadCtx))
+
+	var buf bytes.Buffer
+	file, err := c.ExportTo(context.Background(), &buf, "f1", &model.ExportRequest{ExportType: "pdf"},
+		WithPollInterval(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("ExportTo() error = %v", err)
+	}
+	if buf.String() != "%PDF-1.7" || file.Size != 8 || file.FileName != "report.pdf" {
+		t.Fatalf("ExportTo() = %+v, content %q", file, buf.String())
+	}
+}
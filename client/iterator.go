@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// AllDocuments 返回逐个遍历文档列表的迭代器，自动按 Next 翻页直到最后一页。
+//
+// params 为 nil 时使用默认参数，params.Start 为起始位置，params.Limit 为每页数量，params 不会被修改。
+// 可传入任意 TencentDocClient 实现，如 *Client 或 tdoctest.FakeClient。
+//
+// 出错时产出一次 (nil, err) 后结束，可能的错误包括：
+//   - ListDocuments 返回的错误
+//   - ctx 被取消或超时（ctx.Err()）
+//   - 服务端返回的 Next 没有前进（ErrPaginationStalled）
+//
+// 示例：
+//
+//	for doc, err := range client.AllDocuments(ctx, docClient, &model.ListParams{FolderID: folderID}) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(doc.Title)
+//	}
+func AllDocuments(
+	ctx context.Context,
+	c TencentDocClient,
+	params *model.ListParams,
+	opts ...CallOption,
+) iter.Seq2[*model.Document, error] {
+	var p model.ListParams
+	if params != nil {
+		p = *params
+	}
+
+	return func(yield func(*model.Document, error) bool) {
+		page := p
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			resp, err := c.ListDocuments(ctx, &page, opts...)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, doc := range resp.Data.List {
+				if !yield(doc, nil) {
+					return
+				}
+			}
+
+			// Next 为0或本页为空表示已到最后一页
+			if resp.Data.Next == 0 || len(resp.Data.List) == 0 {
+				return
+			}
+			if resp.Data.Next <= page.Start {
+				yield(nil, fmt.Errorf("%w: start=%d, next=%d", ErrPaginationStalled, page.Start, resp.Data.Next))
+				return
+			}
+			page.Start = resp.Data.Next
+		}
+	}
+}
+
+// AllSearchResults 返回逐个遍历全部搜索结果的迭代器，按 Next 翻页，HasMore 为 false 或偏移量达到 Total 时结束。
+//
+// params 为 nil 时使用默认参数，params.Offset 为起始偏移量，params.Size 为每页数量，params 不会被修改。
+// maxResults 大于0时最多产出 maxResults 个结果。
+// 扫描期间文档被修改可能导致结果在页间移动，已产出过的文档ID会被跳过，每个文档最多产出一次。
+//
+// 出错时产出一次 (nil, err) 后结束，可能的错误包括：
+//   - SearchDocuments 返回的错误
+//   - ctx 被取消或超时（ctx.Err()）
+//   - 服务端返回的 Next 没有前进（ErrPaginationStalled）
+func AllSearchResults(
+	ctx context.Context,
+	c TencentDocClient,
+	params *model.SearchParams,
+	maxResults int,
+	opts ...CallOption,
+) iter.Seq2[*model.SearchDocument, error] {
+	var p model.SearchParams
+	if params != nil {
+		p = *params
+	}
+
+	return func(yield func(*model.SearchDocument, error) bool) {
+		page := p
+		seen := make(map[string]bool)
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			resp, err := c.SearchDocuments(ctx, &page, opts...)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, doc := range resp.Data.List {
+				if seen[doc.ID] {
+					continue
+				}
+				seen[doc.ID] = true
+				if !yield(doc, nil) {
+					return
+				}
+				if maxResults > 0 && len(seen) >= maxResults {
+					return
+				}
+			}
+
+			if !resp.Data.HasMore || len(resp.Data.List) == 0 {
+				return
+			}
+			if resp.Data.Total > 0 && resp.Data.Next >= resp.Data.Total {
+				return
+			}
+			if resp.Data.Next <= page.Offset {
+				yield(nil, fmt.Errorf("%w: offset=%d, next=%d", ErrPaginationStalled, page.Offset, resp.Data.Next))
+				return
+			}
+			page.Offset = resp.Data.Next
+		}
+	}
+}
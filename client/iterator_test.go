@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// pagedClient 按固定页大小返回 total 个文档，next 可覆盖服务端返回的下一页位置
+type pagedClient struct {
+	TencentDocClient
+	total int
+	size  int
+	next  func(start int) int
+	calls int
+}
+
+func (c *pagedClient) ListDocuments(_ context.Context, params *model.ListParams, _ ...CallOption) (*model.ListDocumentsResponse, error) {
+	c.calls++
+	var resp model.ListDocumentsResponse
+	end := min(params.Start+c.size, c.total)
+	for i := params.Start; i < end; i++ {
+		resp.Data.List = append(resp.Data.List, &model.Document{ID: fmt.Sprintf("doc%d", i)})
+	}
+	if end < c.total {
+		resp.Data.Next = end
+	}
+	if c.next != nil {
+		resp.Data.Next = c.next(params.Start)
+	}
+	return &resp, nil
+}
+
+func TestAllDocumentsPagesThroughFolder(t *testing.T) {
+	t.Parallel()
+
+	c := &pagedClient{total: 45, size: 20}
+
+	var ids []string
+	for doc, err := range AllDocuments(context.Background(), c, &model.ListParams{FolderID: "f"}) {
+		if err != nil {
+			t.Fatalf("AllDocuments() error = %v", err)
+		}
+		ids = append(ids, doc.ID)
+	}
+	if len(ids) != 45 || ids[44] != "doc44" {
+		t.Fatalf("AllDocuments() yielded %d docs, last %q", len(ids), ids[len(ids)-1])
+	}
+	if c.calls != 3 {
+		t.Fatalf("ListDocuments calls = %d, want 3", c.calls)
+	}
+}
+
+func TestAllDocumentsStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	c := &pagedClient{total: 45, size: 20}
+
+	n := 0
+	for range AllDocuments(context.Background(), c, nil) {
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	if c.calls != 1 {
+		t.Fatalf("ListDocuments calls = %d, want 1", c.calls)
+	}
+}
+
+func TestAllDocumentsDetectsStalledCursor(t *testing.T) {
+	t.Parallel()
+
+	c := &pagedClient{total: 45, size: 20, next: func(int) int { return 20 }}
+
+	var last error
+	n := 0
+	for _, err := range AllDocuments(context.Background(), c, nil) {
+		if err != nil {
+			last = err
+			continue
+		}
+		n++
+	}
+	if !errors.Is(last, ErrPaginationStalled) {
+		t.Fatalf("AllDocuments() error = %v, want ErrPaginationStalled", last)
+	}
+	if n != 40 {
+		t.Fatalf("AllDocuments() yielded %d docs before stalling, want 40", n)
+	}
+}
+
+func TestAllDocumentsHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	c := &pagedClient{total: 45, size: 20}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var last error
+	for _, err := range AllDocuments(ctx, c, nil) {
+		if err != nil {
+			last = err
+			continue
+		}
+		cancel()
+	}
+	if !errors.Is(last, context.Canceled) {
+		t.Fatalf("AllDocuments() error = %v, want context.Canceled", last)
+	}
+	if c.calls != 1 {
+		t.Fatalf("ListDocuments calls = %d, want 1", c.calls)
+	}
+}
+
+// searchPages 按顺序返回预设的搜索结果页
+type searchPages struct {
+	TencentDocClient
+	pages   []*model.SearchDocumentsResponse
+	offsets []int
+}
+
+func (c *searchPages) SearchDocuments(_ context.Context, params *model.SearchParams, _ ...CallOption) (*model.SearchDocumentsResponse, error) {
+	c.offsets = append(c.offsets, params.Offset)
+	resp := c.pages[0]
+	c.pages = c.pages[1:]
+	return resp, nil
+}
+
+func searchPage(next, total int, hasMore bool, ids ...string) *model.SearchDocumentsResponse {
+	var resp model.SearchDocumentsResponse
+	resp.Data.Next = next
+	resp.Data.Total = total
+	resp.Data.HasMore = hasMore
+	for _, id := range ids {
+		resp.Data.List = append(resp.Data.List, &model.SearchDocument{ID: id})
+	}
+	return &resp
+}
+
+func TestAllSearchResultsDeduplicatesShiftedHits(t *testing.T) {
+	t.Parallel()
+
+	c := &searchPages{pages: []*model.SearchDocumentsResponse{
+		searchPage(2, 5, true, "a", "b"),
+		searchPage(4, 5, true, "b", "c"), // a 被修改后排序变化，b 移到了下一页
+		searchPage(5, 5, false, "d"),
+	}}
+
+	var ids []string
+	for doc, err := range AllSearchResults(context.Background(), c, &model.SearchParams{SearchKey: "周报", Size: 2}, 0) {
+		if err != nil {
+			t.Fatalf("AllSearchResults() error = %v", err)
+		}
+		ids = append(ids, doc.ID)
+	}
+	if fmt.Sprint(ids) != "[a b c d]" {
+		t.Fatalf("AllSearchResults() = %v, want [a b c d]", ids)
+	}
+	if fmt.Sprint(c.offsets) != "[0 2 4]" {
+		t.Fatalf("offsets = %v, want [0 2 4]", c.offsets)
+	}
+}
+
+func TestAllSearchResultsCapsResults(t *testing.T) {
+	t.Parallel()
+
+	c := &searchPages{pages: []*model.SearchDocumentsResponse{
+		searchPage(2, 6, true, "a", "b"),
+		searchPage(4, 6, true, "c", "d"),
+	}}
+
+	n := 0
+	for _, err := range AllSearchResults(context.Background(), c, nil, 3) {
+		if err != nil {
+			t.Fatalf("AllSearchResults() error = %v", err)
+		}
+		n++
+	}
+	if n != 3 || len(c.offsets) != 2 {
+		t.Fatalf("AllSearchResults() yielded %d results in %d pages, want 3 in 2", n, len(c.offsets))
+	}
+}
+
+func TestAllSearchResultsStopsOnTotalAndStall(t *testing.T) {
+	t.Parallel()
+
+	c := &searchPages{pages: []*model.SearchDocumentsResponse{
+		searchPage(2, 2, true, "a", "b"),
+	}}
+	for _, err := range AllSearchResults(context.Background(), c, nil, 0) {
+		if err != nil {
+			t.Fatalf("AllSearchResults() error = %v", err)
+		}
+	}
+	if len(c.offsets) != 1 {
+		t.Fatalf("pages requested = %d, want 1", len(c.offsets))
+	}
+
+	c = &searchPages{pages: []*model.SearchDocumentsResponse{
+		searchPage(0, 10, true, "a", "b"),
+	}}
+	var last error
+	for _, err := range AllSearchResults(context.Background(), c, nil, 0) {
+		last = err
+	}
+	if !errors.Is(last, ErrPaginationStalled) {
+		t.Fatalf("AllSearchResults() error = %v, want ErrPaginationStalled", last)
+	}
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+// Manager 多用户客户端管理器
+//
+// Manager 持有一份应用配置和共享的 HTTP 连接池，为每个用户分配独立的 Client。
+// 每个 Client 拥有自己的令牌、锁和刷新流程，不同用户之间互不影响，适用于服务端代表大量用户调用接口的场景。
+// 配置 TokenStore 后，用户令牌会在首次调用接口时从存储中加载，刷新后自动写回。
+type Manager struct {
+	config     *config.Config
+	executor   *util.Executor
+	downloader *util.Executor
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewManager 创建多用户客户端管理器，配置中的 InitialToken 不会被使用，配置无效时 panic
+func NewManager(opts ...config.Option) *Manager {
+	cfg := buildConfig(opts)
+	// 令牌按用户区分，不能让所有用户共享 InitialToken，也不能让 ExchangeToken 直接返回它
+	cfg.InitialToken = nil
+	executor, downloader := newExecutors(cfg)
+
+	return &Manager{
+		config:     cfg,
+		executor:   executor,
+		downloader: downloader,
+		clients:    make(map[string]*Client),
+	}
+}
+
+// ForUser 返回 openID 对应用户的客户端，同一用户多次调用返回同一实例
+//
+// 返回的客户端并发安全，首次调用接口时从 TokenStore 加载令牌；
+// 未配置 TokenStore 时需通过 WithToken 设置令牌。
+func (m *Manager) ForUser(openID string) *Client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if c, ok := m.clients[openID]; ok {
+		return c
+	}
+
+	c := newClient(m.config, m.executor, m.downloader, openID)
+	m.clients[openID] = c
+	return c
+}
+
+// GetAuthURL 获取OAuth授权URL
+func (m *Manager) GetAuthURL() string {
+	return newClient(m.config, m.executor, m.downloader, "").GetAuthURL()
+}
+
+// ExchangeToken 使用授权码换取令牌，并返回该用户的客户端
+//
+// 令牌会设置到用户客户端上，并在配置了 TokenStore 时写回存储。
+func (m *Manager) ExchangeToken(ctx context.Context, code string, opts ...CallOption) (*Client, error) {
+	exchanged := newClient(m.config, m.executor, m.downloader, "")
+	resp, err := exchanged.ExchangeToken(ctx, code, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.UserID == "" {
+		return nil, fmt.Errorf("exchange token failed: empty user id in response")
+	}
+
+	c := m.ForUser(resp.UserID)
+	c.setToken(exchanged.currentToken())
+	return c, nil
+}
+
+// Remove 移除 openID 对应用户的客户端缓存，不会删除 TokenStore 中的令牌
+func (m *Manager) Remove(openID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.clients, openID)
+}
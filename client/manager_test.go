@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/store"
+)
+
+func TestManagerIsolatesUsers(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		openID := req.Header.Get("Open-Id")
+		if req.Header.Get("Access-Token") != "token-"+openID {
+			return jsonResponse(`{"ret":400006,"msg":"invalid token"}`), nil
+		}
+		return jsonResponse(fmt.Sprintf(`{"ret":0,"msg":"ok","data":{"ID":"%s"}}`, openID)), nil
+	})
+
+	ctx := context.Background()
+	tokens := store.NewMemoryStore()
+	users := []string{"u1", "u2", "u3"}
+	for _, u := range users {
+		if err := tokens.Save(ctx, u, &model.Token{AccessToken: "token-" + u, UserID: u}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	m := NewManager(config.WithHttpTransport(transport), config.WithTokenStore(tokens))
+	if m.ForUser("u1") != m.ForUser("u1") {
+		t.Fatal("ForUser() returned different clients for the same user")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		u := users[i%len(users)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := m.ForUser(u).GetFileMetadata(ctx, "file")
+			if err != nil {
+				t.Errorf("GetFileMetadata(%s) error = %v", u, err)
+				return
+			}
+			if resp.Data.ID != u {
+				t.Errorf("GetFileMetadata(%s) answered for %s", u, resp.Data.ID)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := m.ForUser("unknown").GetFileMetadata(ctx, "file"); err == nil {
+		t.Fatal("GetFileMetadata() for unknown user error = nil, want error")
+	}
+}
+
+func TestManagerIgnoresInitialToken(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(`{"access_token":"token-u2","refresh_token":"r","user_id":"u2","expires_in":7200}`), nil
+	})
+	m := NewManager(
+		config.WithHttpTransport(transport),
+		config.WithInitialToken(&model.Token{AccessToken: "token-u1", UserID: "u1"}),
+	)
+
+	c, err := m.ExchangeToken(context.Background(), "code")
+	if err != nil {
+		t.Fatalf("ExchangeToken() error = %v", err)
+	}
+	if token := c.currentToken(); c != m.ForUser("u2") || token == nil || token.AccessToken != "token-u2" {
+		t.Fatalf("ExchangeToken() token = %+v, want token-u2 for u2", token)
+	}
+	if token := m.ForUser("u3").currentToken(); token != nil {
+		t.Fatalf("ForUser(u3) token = %+v, want nil", token)
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+// CallOption 单次调用选项，可传给 TencentDocClient 的各个方法
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout  time.Duration
+	header   http.Header
+	retry    *util.RetryPolicy
+	retrySet bool
+	metadata *ResponseMetadata
+}
+
+// WithCallTimeout 设置本次调用的超时时间，代替 config.Timeout，包含重试和令牌刷新的耗时
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHeader 为本次调用的请求添加请求头，可多次使用；SDK 设置的鉴权请求头不会被覆盖
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithRetry 设置本次调用的重试策略，代替 config.RetryPolicy，policy 为空时不重试
+func WithRetry(policy *util.RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = policy
+		o.retrySet = true
+	}
+}
+
+// WithoutRetry 本次调用不重试，适用于不希望重复提交的请求
+func WithoutRetry() CallOption {
+	return WithRetry(nil)
+}
+
+// WithResponseMetadata 在调用返回后将响应元数据写入 md，调用失败时同样会写入已收到的响应
+//
+// 调用过程中自动刷新令牌并重试时，md 记录的是最后一次请求的响应。
+func WithResponseMetadata(md *ResponseMetadata) CallOption {
+	return func(o *callOptions) {
+		o.metadata = md
+	}
+}
+
+// ResponseMetadata 响应元数据
+type ResponseMetadata struct {
+	StatusCode int           // HTTP 状态码
+	Header     http.Header   // 响应头
+	RequestID  string        // 服务端请求ID
+	Duration   time.Duration // 从发送请求到读取完响应体的耗时，包含重试
+	Attempts   int           // 实际发送的请求次数，包含重试
+}
+
+func (md *ResponseMetadata) fill(resp *util.Response) {
+	md.StatusCode = resp.StatusCode
+	md.Header = resp.Header
+	md.RequestID = resp.RequestID
+	md.Duration = resp.Duration
+	md.Attempts = resp.Attempts
+}
+
+type callOptionsKey struct{}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// callOptionsFrom 返回 instrument 写入 ctx 的调用选项
+func callOptionsFrom(ctx context.Context) *callOptions {
+	if o, ok := ctx.Value(callOptionsKey{}).(*callOptions); ok {
+		return o
+	}
+	return &callOptions{}
+}
+
+// send 使用 execute 发送请求，附加调用选项中的请求头并记录响应元数据
+func (c *Client) send(
+	ctx context.Context,
+	execute func(context.Context, *util.Request, interface{}) (*util.Response, error),
+	req *util.Request,
+	result interface{},
+) error {
+	o := callOptionsFrom(ctx)
+	if len(o.header) > 0 {
+		withHeader := *req
+		withHeader.Header = o.header.Clone()
+		for key, values := range req.Header {
+			withHeader.Header[key] = values
+		}
+		req = &withHeader
+	}
+
+	resp, err := execute(ctx, req, result)
+	if o.metadata != nil && resp != nil {
+		o.metadata.fill(resp)
+	}
+	return err
+}
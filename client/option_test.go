@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+func TestCallOptionHeadersAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if got := req.Header.Get("X-Biz-Id"); got != "job-1" {
+			t.Errorf("X-Biz-Id = %q, want job-1", got)
+		}
+		if got := req.Header.Get("Access-Token"); got != "a" {
+			t.Errorf("Access-Token = %q, want a", got)
+		}
+		resp := jsonResponse(`{"ret":0,"msg":"ok","data":{}}`)
+		resp.Header.Set("X-Request-Id", "req-1")
+		return resp, nil
+	})
+
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+
+	var md ResponseMetadata
+	_, err := c.GetFileMetadata(context.Background(), "f1",
+		WithHeader("X-Biz-Id", "job-1"),
+		WithHeader("Access-Token", "spoofed"),
+		WithResponseMetadata(&md),
+	)
+	if err != nil {
+		t.Fatalf("GetFileMetadata() error = %v", err)
+	}
+	if md.StatusCode != http.StatusOK || md.RequestID != "req-1" || md.Header.Get("X-Request-Id") != "req-1" {
+		t.Fatalf("metadata = %+v", md)
+	}
+}
+
+func TestCallOptionTimeoutOverridesConfig(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithTimeout(time.Hour),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+
+	start := time.Now()
+	_, err := c.ListDocuments(context.Background(), &model.ListParams{}, WithCallTimeout(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ListDocuments() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("ListDocuments() took %v", elapsed)
+	}
+}
+
+func TestCallOptionRetryOverride(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	policy := util.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithRetryPolicy(policy),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+	ctx := context.Background()
+
+	if _, err := c.GetFileMetadata(ctx, "f1", WithoutRetry()); err == nil {
+		t.Fatal("GetFileMetadata() error = nil")
+	}
+	if got := calls.Swap(0); got != 1 {
+		t.Fatalf("calls with WithoutRetry = %d, want 1", got)
+	}
+
+	if _, err := c.GetFileMetadata(ctx, "f1"); err == nil {
+		t.Fatal("GetFileMetadata() error = nil")
+	}
+	if got := calls.Swap(0); got != 3 {
+		t.Fatalf("calls with config policy = %d, want 3", got)
+	}
+
+	// 未配置重试策略的客户端也可以为单次调用开启重试
+	c = NewClient(
+		config.WithHttpTransport(transport),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+	policy.MaxAttempts = 2
+	if _, err := c.GetFileMetadata(ctx, "f1", WithRetry(policy)); err == nil {
+		t.Fatal("GetFileMetadata() error = nil")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls with WithRetry = %d, want 2", got)
+	}
+}
+
+func TestResponseMetadataOnError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp := jsonResponse(`{"ret":400004,"msg":"not found"}`)
+		if calls.Add(1) == 1 {
+			resp = &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: http.NoBody}
+		}
+		resp.Header.Set("X-Request-Id", "req-9")
+		return resp, nil
+	})
+
+	policy := util.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithRetryPolicy(policy),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+
+	var md ResponseMetadata
+	_, err := c.GetFileMetadata(context.Background(), "f1", WithResponseMetadata(&md))
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("GetFileMetadata() error = %v, want model.ErrNotFound", err)
+	}
+	if got := model.RequestIDFromError(err); got != "req-9" {
+		t.Fatalf("RequestIDFromError() = %q, want req-9", got)
+	}
+	if md.Attempts != 2 || md.RequestID != "req-9" || md.StatusCode != http.StatusOK || md.Duration <= 0 {
+		t.Fatalf("metadata = %+v", md)
+	}
+}
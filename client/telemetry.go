@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+// instrument 执行一次 SDK 操作：应用调用选项和超时，再通过 trace 记录操作名和 span
+func (c *Client) instrument(
+	ctx context.Context,
+	operation string,
+	attrs []telemetry.Attribute,
+	opts []CallOption,
+	fn func(ctx context.Context) error,
+) error {
+	ctx, cancel := c.withCallOptions(ctx, opts)
+	defer cancel()
+	return c.trace(ctx, operation, attrs, fn)
+}
+
+// withCallOptions 在 ctx 中应用调用选项和超时，返回的 cancel 需在调用结束后执行
+func (c *Client) withCallOptions(ctx context.Context, opts []CallOption) (context.Context, context.CancelFunc) {
+	ctx, timeout := c.applyCallOptions(ctx, opts)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withHeaderTimeout 在 ctx 中应用调用选项，超时只限制到调用 received 为止，用于下载等需要在返回后继续读取响应体的请求
+//
+// 收到响应头后调用 received，超时已过时返回 context.DeadlineExceeded；返回的 cancel 需在读取完响应体后执行。
+func (c *Client) withHeaderTimeout(ctx context.Context, opts []CallOption) (context.Context, func() error, context.CancelFunc) {
+	ctx, timeout := c.applyCallOptions(ctx, opts)
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := func() bool { return true }
+	if timeout > 0 {
+		stop = time.AfterFunc(timeout, func() { cancel(context.DeadlineExceeded) }).Stop
+	}
+	received := func() error {
+		if !stop() {
+			return context.DeadlineExceeded
+		}
+		return nil
+	}
+	return ctx, received, func() { cancel(nil) }
+}
+
+// applyCallOptions 在 ctx 中应用调用选项，返回本次调用的超时时间，由调用方决定如何应用
+func (c *Client) applyCallOptions(ctx context.Context, opts []CallOption) (context.Context, time.Duration) {
+	o := newCallOptions(opts)
+	timeout := c.config.Timeout
+	if o.timeout > 0 {
+		timeout = o.timeout
+	}
+	if o.retrySet {
+		ctx = util.ContextWithRetryPolicy(ctx, o.retry)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, o), timeout
+}
+
+// trace 在 ctx 中记录操作名供指标使用，配置了 Tracer 时创建 span 并在结束时记录 ret，
+// fn 中可通过 telemetry.SpanFromContext 获取 span
+func (c *Client) trace(
+	ctx context.Context,
+	operation string,
+	attrs []telemetry.Attribute,
+	fn func(ctx context.Context) error,
+) error {
+	ctx = telemetry.ContextWithOperation(ctx, operation)
+	if c.config.Tracer == nil {
+		return fn(ctx)
+	}
+
+	ctx, span := c.config.Tracer.Start(ctx, operation, attrs...)
+	err := fn(telemetry.ContextWithSpan(ctx, span))
+
+	var apiErr *model.APIError
+	switch {
+	case err == nil:
+		span.SetAttributes(telemetry.Int(telemetry.AttrRet, 0))
+	case errors.As(err, &apiErr) && apiErr.Ret != 0:
+		span.SetAttributes(telemetry.Int(telemetry.AttrRet, apiErr.Ret))
+	}
+	span.End(err)
+	return err
+}
+
+// exportTTL 导出任务超过该时间仍未完成时不再统计耗时
+const exportTTL = time.Hour
+
+// exportTracker 记录由 ExportDocument 创建的导出任务，在 GetExportProgress 返回进度100时统计耗时
+type exportTracker struct {
+	mu      sync.Mutex
+	pending map[string]pendingExport // key 为 operationID
+}
+
+type pendingExport struct {
+	exportType string
+	start      time.Time
+}
+
+func (t *exportTracker) start(operationID, exportType string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending == nil {
+		t.pending = make(map[string]pendingExport)
+	}
+	// 清理长时间未完成的任务，避免调用方不再轮询时无限增长
+	for id, export := range t.pending {
+		if now.Sub(export.start) > exportTTL {
+			delete(t.pending, id)
+		}
+	}
+	t.pending[operationID] = pendingExport{exportType: exportType, start: now}
+}
+
+// finish 返回任务的导出类型和耗时，任务不存在时 ok 为 false
+func (t *exportTracker) finish(operationID string, now time.Time) (exportType string, duration time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	export, ok := t.pending[operationID]
+	if !ok {
+		return "", 0, false
+	}
+	delete(t.pending, operationID)
+	return export.exportType, now.Sub(export.start), true
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+type recordedSpan struct {
+	operation string
+	attrs     map[string]interface{}
+	err       error
+}
+
+type testSpanKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, operation string, attrs ...telemetry.Attribute) (context.Context, telemetry.Span) {
+	span := &recordedSpan{operation: operation, attrs: make(map[string]interface{})}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	s := &testSpan{tracer: t, span: span}
+	s.SetAttributes(attrs...)
+	return context.WithValue(ctx, testSpanKey{}, operation), s
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	if operation, ok := ctx.Value(testSpanKey{}).(string); ok {
+		header.Set("X-Test-Span", operation)
+	}
+}
+
+type testSpan struct {
+	tracer *recordingTracer
+	span   *recordedSpan
+}
+
+func (s *testSpan) SetAttributes(attrs ...telemetry.Attribute) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	for _, attr := range attrs {
+		s.span.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) End(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.err = err
+}
+
+func TestClientTracesOperations(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("X-Test-Span") != "ExportDocument" {
+			t.Errorf("trace context not injected: %v", req.Header)
+		}
+		if calls.Add(1) == 1 {
+			return jsonResponse(`{"ret":400009,"msg":"rate limited"}`), nil
+		}
+		return jsonResponse(`{"ret":0,"msg":"ok","data":{"operationID":"op1"}}`), nil
+	})
+
+	policy := util.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	tracer := &recordingTracer{}
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithRetryPolicy(policy),
+		config.WithTracer(tracer),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+
+	if _, err := c.ExportDocument(context.Background(), "f1", &model.ExportRequest{ExportType: "pdf"}); err != nil {
+		t.Fatalf("ExportDocument() error = %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	want := map[string]interface{}{
+		telemetry.AttrFileID:     "f1",
+		telemetry.AttrExportType: "pdf",
+		telemetry.AttrRetries:    1,
+		telemetry.AttrRet:        0,
+	}
+	for key, value := range want {
+		if span.attrs[key] != value {
+			t.Errorf("attribute %s = %v, want %v", key, span.attrs[key], value)
+		}
+	}
+	if span.operation != "ExportDocument" || span.err != nil {
+		t.Fatalf("span = %+v", span)
+	}
+}
+
+func TestClientTracesAPIErrorRet(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(`{"ret":400004,"msg":"not found"}`), nil
+	})
+
+	tracer := &recordingTracer{}
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithTracer(tracer),
+		config.WithInitialToken(&model.Token{AccessToken: "a", UserID: "u1"}),
+	)
+
+	if _, err := c.GetFileMetadata(context.Background(), "missing"); err == nil {
+		t.Fatal("GetFileMetadata() error = nil")
+	}
+
+	span := tracer.spans[0]
+	if span.attrs[telemetry.AttrRet] != 400004 || span.err == nil {
+		t.Fatalf("span = %+v", span)
+	}
+}
+
+type recordingMetrics struct {
+	mu        sync.Mutex
+	requests  []telemetry.RequestInfo
+	refreshes []error
+	exports   []string
+	bytes     int64
+}
+
+func (m *recordingMetrics) ObserveRequest(info telemetry.RequestInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, info)
+}
+
+func (m *recordingMetrics) ObserveTokenRefresh(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshes = append(m.refreshes, err)
+}
+
+func (m *recordingMetrics) ObserveExport(exportType string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exports = append(m.exports, exportType)
+}
+
+func (m *recordingMetrics) AddDownloadedBytes(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes += n
+}
+
+func TestClientRecordsMetrics(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.URL.Host == "cos.example.com":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Disposition": []string{`attachment; filename="doc.pdf"`}},
+				Body:       io.NopCloser(strings.NewReader("12345")),
+			}, nil
+		case req.URL.String() == constant.TokenEndpoint:
+			return jsonResponse(`{"access_token":"new","refresh_token":"r2","expires_in":7200}`), nil
+		case strings.HasSuffix(req.URL.Path, "/async-export"):
+			if req.Header.Get("Access-Token") != "new" {
+				return jsonResponse(`{"ret":400007,"msg":"access token expired"}`), nil
+			}
+			return jsonResponse(`{"ret":0,"msg":"ok","data":{"operationID":"op1"}}`), nil
+		default:
+			return jsonResponse(`{"ret":0,"msg":"ok","data":{"progress":100,"url":"https://cos.example.com/1"}}`), nil
+		}
+	})
+
+	metrics := &recordingMetrics{}
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithMetrics(metrics),
+		config.WithInitialToken(&model.Token{AccessToken: "old", RefreshToken: "r1", UserID: "u1"}),
+	)
+	ctx := context.Background()
+
+	if _, err := c.ExportDocument(ctx, "f1", &model.ExportRequest{ExportType: "pdf"}); err != nil {
+		t.Fatalf("ExportDocument() error = %v", err)
+	}
+	progress, err := c.GetExportProgress(ctx, "f1", "op1")
+	if err != nil {
+		t.Fatalf("GetExportProgress() error = %v", err)
+	}
+	if _, err := c.DownloadFromCOS(ctx, progress.Data.URL, t.TempDir()); err != nil {
+		t.Fatalf("DownloadFromCOS() error = %v", err)
+	}
+
+	var operations []string
+	for _, info := range metrics.requests {
+		operations = append(operations, info.Operation)
+	}
+	want := "ExportDocument,RefreshToken,ExportDocument,GetExportProgress,DownloadFromCOS"
+	if got := strings.Join(operations, ","); got != want {
+		t.Fatalf("operations = %s, want %s", got, want)
+	}
+	if info := metrics.requests[0]; !info.HasRet || info.Ret != 400007 || info.StatusCode != http.StatusOK {
+		t.Fatalf("first request = %+v", info)
+	}
+	if len(metrics.refreshes) != 1 || metrics.refreshes[0] != nil {
+		t.Fatalf("refreshes = %v", metrics.refreshes)
+	}
+	if strings.Join(metrics.exports, ",") != "pdf" {
+		t.Fatalf("exports = %v", metrics.exports)
+	}
+	if metrics.bytes != 5 {
+		t.Fatalf("downloaded bytes = %d, want 5", metrics.bytes)
+	}
+}
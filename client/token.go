@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// Token 返回客户端当前持有的访问令牌副本，未设置时返回 nil。
+//
+// 自动刷新后令牌会发生变化，如需持久化请在调用接口后重新获取。
+func (c *Client) Token() *model.Token {
+	token := c.currentToken()
+	if token == nil {
+		return nil
+	}
+	cp := *token
+	return &cp
+}
+
+// ForToken 返回绑定到 token 的客户端副本，原客户端不受影响。
+//
+// 副本共享配置、连接池、限流器等资源，创建开销很小，适合在服务端为每个请求或用户派生客户端。
+// 副本的令牌独立维护，自动刷新后的令牌按 UserID 写回 TokenStore。
+func (c *Client) ForToken(token *model.Token) *Client {
+	cp := newClient(c.config, c.executor, c.downloader, "")
+	cp.exports = c.exports
+	cp.setToken(token)
+	return cp
+}
+
+type tokenKey struct{}
+
+// ContextWithToken 返回携带访问令牌的 ctx，使用该 ctx 的调用以 token 代替客户端持有的令牌。
+//
+// 该令牌按原样使用：不会自动刷新，也不会写回客户端或 TokenStore。需要自动刷新时请使用 ForToken。
+func ContextWithToken(ctx context.Context, token *model.Token) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+func tokenFromContext(ctx context.Context) *model.Token {
+	token, _ := ctx.Value(tokenKey{}).(*model.Token)
+	return token
+}
+
+func (c *Client) currentToken() *model.Token {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// LoadToken 从配置的 TokenStore 中加载 openID 对应的令牌并设置到客户端
+//
+// 未配置 TokenStore 时返回错误；令牌不存在时返回 store.ErrNotFound。
+func (c *Client) LoadToken(ctx context.Context, openID string) error {
+	if c.config.TokenStore == nil {
+		return fmt.Errorf("token store is not configured")
+	}
+
+	token, err := c.config.TokenStore.Load(ctx, openID)
+	if err != nil {
+		return fmt.Errorf("load token failed: %w", err)
+	}
+	if token.UserID == "" {
+		token.UserID = openID
+	}
+
+	c.setToken(token)
+	return nil
+}
+
+// loadBoundToken 从 TokenStore 加载客户端绑定用户的令牌，并发调用时只加载一次
+func (c *Client) loadBoundToken(ctx context.Context) (*model.Token, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if token := c.currentToken(); token != nil {
+		return token, nil
+	}
+	if err := c.LoadToken(ctx, c.openID); err != nil {
+		return nil, err
+	}
+	return c.currentToken(), nil
+}
+
+// storeToken 设置客户端令牌并写回 TokenStore
+func (c *Client) storeToken(ctx context.Context, token *model.Token) error {
+	c.setToken(token)
+	return c.saveToken(ctx, c.currentToken())
+}
+
+// saveToken 配置了 TokenStore 时将令牌按 UserID 写回，不修改客户端持有的令牌
+func (c *Client) saveToken(ctx context.Context, token *model.Token) error {
+	if c.config.TokenStore == nil || token.UserID == "" {
+		return nil
+	}
+	if err := c.config.TokenStore.Save(ctx, token.UserID, token); err != nil {
+		return fmt.Errorf("save token failed: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) setToken(token *model.Token) {
+	if token != nil {
+		cp := *token
+		stampExpiresAt(&cp)
+		token = &cp
+	}
+
+	c.mu.Lock()
+	c.token = token
+	c.mu.Unlock()
+}
+
+// needsRefresh 判断令牌是否已进入自动刷新窗口
+func (c *Client) needsRefresh(token *model.Token) bool {
+	if token.ExpiresAt == 0 || token.RefreshToken == "" {
+		return false
+	}
+	deadline := time.Unix(token.ExpiresAt, 0).Add(-c.config.RefreshBefore)
+	return !time.Now().Before(deadline)
+}
+
+// validToken 返回可用的访问令牌，令牌即将过期时先自动刷新
+func (c *Client) validToken(ctx context.Context) (*model.Token, error) {
+	token := c.currentToken()
+	if token == nil && c.openID != "" && c.config.TokenStore != nil {
+		var err error
+		if token, err = c.loadBoundToken(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if token == nil || token.AccessToken == "" {
+		return nil, fmt.Errorf("access token is required")
+	}
+	if !c.needsRefresh(token) {
+		return token, nil
+	}
+
+	refreshed, err := c.refreshAccessToken(ctx, token)
+	if err != nil {
+		// 令牌尚未真正过期时继续使用旧令牌，由接口返回决定是否需要再次刷新
+		if time.Now().Unix() < token.ExpiresAt {
+			return token, nil
+		}
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// refreshAccessToken 使用 stale 中的刷新令牌换取新令牌并写回客户端。
+//
+// 多个协程同时发现令牌过期时只会执行一次刷新，后到的协程直接复用刷新结果。
+func (c *Client) refreshAccessToken(ctx context.Context, stale *model.Token) (*model.Token, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// 等待锁期间其他协程可能已经完成刷新
+	if current := c.currentToken(); current != nil && current.AccessToken != stale.AccessToken {
+		return current, nil
+	}
+
+	if stale.RefreshToken == "" {
+		return nil, fmt.Errorf("access token expired and no refresh token available")
+	}
+
+	resp, err := c.requestRefresh(ctx, stale.RefreshToken, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.storeToken(ctx, mergeRefreshed(stale, &resp.Token)); err != nil {
+		return nil, err
+	}
+	return c.currentToken(), nil
+}
+
+// mergeRefreshed 合并刷新结果，刷新接口未返回的字段沿用旧令牌
+func mergeRefreshed(stale, refreshed *model.Token) *model.Token {
+	token := *refreshed
+	if token.UserID == "" {
+		token.UserID = stale.UserID
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = stale.RefreshToken
+	}
+	return &token
+}
+
+// stampExpiresAt 根据 ExpiresIn 计算过期时间，视为令牌刚刚签发
+func stampExpiresAt(token *model.Token) {
+	if token.ExpiresAt == 0 && token.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Unix() + int64(token.ExpiresIn)
+	}
+}
+
+// withToken 使用有效令牌执行 call，若返回令牌过期或失效错误则刷新令牌后重试一次。
+//
+// ctx 中通过 ContextWithToken 指定了令牌时直接使用该令牌，不刷新。
+func (c *Client) withToken(ctx context.Context, call func(token *model.Token) error) error {
+	if token := tokenFromContext(ctx); token != nil {
+		if token.AccessToken == "" {
+			return fmt.Errorf("access token is required")
+		}
+		return call(token)
+	}
+
+	token, err := c.validToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = call(token)
+	if !isTokenError(err) {
+		return err
+	}
+
+	token, refreshErr := c.refreshAccessToken(ctx, token)
+	if refreshErr != nil {
+		return fmt.Errorf("%w (refresh failed: %v)", err, refreshErr)
+	}
+
+	return call(token)
+}
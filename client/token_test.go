@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/store"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestClientRefreshesOnExpiredRet(t *testing.T) {
+	t.Parallel()
+
+	var refreshes, lists atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == constant.TokenEndpoint {
+			refreshes.Add(1)
+			return jsonResponse(`{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`), nil
+		}
+
+		lists.Add(1)
+		if req.Header.Get("Access-Token") != "new-access" {
+			return jsonResponse(`{"ret":400007,"msg":"access token expired"}`), nil
+		}
+		return jsonResponse(`{"ret":0,"msg":"ok","data":{"next":0,"list":[]}}`), nil
+	})
+
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithInitialToken(&model.Token{AccessToken: "old-access", RefreshToken: "old-refresh", UserID: "u1"}),
+	)
+
+	if _, err := c.ListDocuments(context.Background(), &model.ListParams{}); err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+
+	if got := refreshes.Load(); got != 1 {
+		t.Fatalf("refresh count = %d, want 1", got)
+	}
+	if got := lists.Load(); got != 2 {
+		t.Fatalf("list request count = %d, want 2", got)
+	}
+
+	token := c.Token()
+	if token.AccessToken != "new-access" || token.RefreshToken != "new-refresh" {
+		t.Fatalf("token not updated: %+v", token)
+	}
+	if token.UserID != "u1" {
+		t.Fatalf("UserID = %q, want %q", token.UserID, "u1")
+	}
+	if token.ExpiresAt == 0 {
+		t.Fatal("ExpiresAt not computed from ExpiresIn")
+	}
+}
+
+func TestClientRefreshesOnceBeforeExpiry(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == constant.TokenEndpoint {
+			refreshes.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return jsonResponse(`{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`), nil
+		}
+		if req.Header.Get("Access-Token") != "new-access" {
+			return jsonResponse(`{"ret":400007,"msg":"access token expired"}`), nil
+		}
+		return jsonResponse(`{"ret":0,"msg":"ok","data":{"next":0,"list":[]}}`), nil
+	})
+
+	c := NewClient(
+		config.WithHttpTransport(transport),
+		config.WithInitialToken(&model.Token{
+			AccessToken:  "old-access",
+			RefreshToken: "old-refresh",
+			ExpiresAt:    time.Now().Add(time.Minute).Unix(),
+		}),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.ListDocuments(context.Background(), &model.ListParams{}); err != nil {
+				t.Errorf("ListDocuments() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := refreshes.Load(); got != 1 {
+		t.Fatalf("refresh count = %d, want 1", got)
+	}
+}
+
+func TestClientWritesRefreshedTokenToStore(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == constant.TokenEndpoint {
+			return jsonResponse(`{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`), nil
+		}
+		return jsonResponse(`{"ret":0,"msg":"ok","data":{}}`), nil
+	})
+
+	tokens := store.NewMemoryStore()
+	ctx := context.Background()
+	if err := tokens.Save(ctx, "u1", &model.Token{AccessToken: "old-access", RefreshToken: "old-refresh", ExpiresAt: 1}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	c := NewClient(config.WithHttpTransport(transport), config.WithTokenStore(tokens))
+	if err := c.LoadToken(ctx, "u1"); err != nil {
+		t.Fatalf("LoadToken() error = %v", err)
+	}
+	if _, err := c.GetFileMetadata(ctx, "file1"); err != nil {
+		t.Fatalf("GetFileMetadata() error = %v", err)
+	}
+
+	saved, err := tokens.Load(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if saved.AccessToken != "new-access" || saved.RefreshToken != "new-refresh" || saved.UserID != "u1" {
+		t.Fatalf("stored token = %+v", saved)
+	}
+}
+
+func TestTokenMethodsRejectEmptyAccessToken(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(`{"ret":400006,"msg":"invalid refresh"}`), nil
+	})
+	ctx := context.Background()
+	c := NewClient(config.WithHttpTransport(transport))
+	c.setToken(&model.Token{AccessToken: "old-access", RefreshToken: "old-refresh"})
+
+	if _, err := c.RefreshToken(ctx, "old-refresh"); err == nil {
+		t.Fatal("RefreshToken() error = nil, want error")
+	}
+	if _, err := c.ExchangeToken(ctx, "code"); err == nil {
+		t.Fatal("ExchangeToken() error = nil, want error")
+	}
+	if token := c.currentToken(); token == nil || token.AccessToken != "old-access" {
+		t.Fatalf("current token = %+v, want unchanged", token)
+	}
+}
+
+func TestTokenErrorRetIsAPIError(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.ParseForm()
+		if req.PostForm.Get("grant_type") == "refresh_token" {
+			return jsonResponse(`{"ret":400006,"msg":"invalid refresh"}`), nil
+		}
+		return jsonResponse(`{"ret":400001,"msg":"invalid code"}`), nil
+	})
+	ctx := context.Background()
+	c := NewClient(config.WithHttpTransport(transport))
+
+	_, err := c.RefreshToken(ctx, "old-refresh")
+	var apiErr *model.APIError
+	if !errors.Is(err, model.ErrInvalidToken) || !errors.As(err, &apiErr) || apiErr.Msg != "invalid refresh" {
+		t.Fatalf("RefreshToken() error = %v, want ErrInvalidToken", err)
+	}
+	if _, err := c.ExchangeToken(ctx, "code"); !errors.Is(err, model.ErrInvalidParams) {
+		t.Fatalf("ExchangeToken() error = %v, want ErrInvalidParams", err)
+	}
+}
+
+func TestTokenOAuthErrorIsInvalidToken(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(`{"error":"invalid_grant","error_description":"code expired"}`), nil
+	})
+	c := NewClient(config.WithHttpTransport(transport))
+
+	_, err := c.ExchangeToken(context.Background(), "code")
+	var apiErr *model.APIError
+	if !errors.Is(err, model.ErrInvalidToken) || !errors.As(err, &apiErr) {
+		t.Fatalf("ExchangeToken() error = %v, want ErrInvalidToken", err)
+	}
+	if apiErr.Msg != "invalid_grant: code expired" {
+		t.Fatalf("APIError.Msg = %q", apiErr.Msg)
+	}
+	if msg := err.Error(); strings.Contains(msg, "status code") || !strings.Contains(msg, "invalid_grant") {
+		t.Fatalf("ExchangeToken() error = %q", msg)
+	}
+}
+
+func TestRefreshTokenPersistsForeignToken(t *testing.T) {
+	t.Parallel()
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.ParseForm()
+		if req.PostForm.Get("refresh_token") == "r1" {
+			return jsonResponse(`{"access_token":"a1-new","refresh_token":"r1-new","user_id":"u1","expires_in":7200}`), nil
+		}
+		return jsonResponse(`{"access_token":"a3-new","refresh_token":"r3-new","expires_in":7200}`), nil
+	})
+	ctx := context.Background()
+	tokens := store.NewMemoryStore()
+
+	// 客户端持有 u2 的令牌，刷新从存储中取出的 u1 令牌
+	c := NewClient(config.WithHttpTransport(transport), config.WithTokenStore(tokens),
+		config.WithInitialToken(&model.Token{AccessToken: "a2", RefreshToken: "r2", UserID: "u2"}))
+	if _, err := c.RefreshToken(ctx, "r1"); err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if saved, err := tokens.Load(ctx, "u1"); err != nil || saved.RefreshToken != "r1-new" {
+		t.Fatalf("stored u1 token = %+v, %v", saved, err)
+	}
+	if token := c.currentToken(); token.AccessToken != "a2" {
+		t.Fatalf("current token = %+v, want u2 token unchanged", token)
+	}
+
+	// 响应不含 user_id 时写回 Manager 绑定的用户
+	m := NewManager(config.WithHttpTransport(transport), config.WithTokenStore(tokens))
+	u3 := m.ForUser("u3")
+	if _, err := u3.RefreshToken(ctx, "r3"); err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if saved, err := tokens.Load(ctx, "u3"); err != nil || saved.RefreshToken != "r3-new" {
+		t.Fatalf("stored u3 token = %+v, %v", saved, err)
+	}
+	if token := u3.currentToken(); token == nil || token.AccessToken != "a3-new" {
+		t.Fatalf("u3 token = %+v, want a3-new", token)
+	}
+}
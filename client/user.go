@@ -3,8 +3,8 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/url"
 
-	"github.com/chinahtl/tencent-doc-sdk/constant"
 	"github.com/chinahtl/tencent-doc-sdk/model"
 	"github.com/chinahtl/tencent-doc-sdk/util"
 )
@@ -23,24 +23,22 @@ import (
 // 可能返回的错误：
 //   - access token未设置
 //   - API调用失败
-//   - 服务端返回错误
+//   - 服务端返回错误（*model.APIError，可通过 errors.Is 判断 model.ErrNotFound 等类型）
 //
 // API参考：https://docs.qq.com/oauth/v2/userinfo
-func (c *Client) GetUserInfo(ctx context.Context) (*model.UserInfo, error) {
-	if c.token.AccessToken == "" {
-		return nil, fmt.Errorf("access token not set")
-	}
-
-	url := fmt.Sprintf("%s?access_token=%s", constant.UserInfoEndpoint, c.token.AccessToken)
-
+func (c *Client) GetUserInfo(ctx context.Context, opts ...CallOption) (*model.UserInfo, error) {
 	var resp model.UserInfoResponse
-	if err := util.HTTPGet(ctx, url, &resp); err != nil {
+	err := c.instrument(ctx, "GetUserInfo", nil, opts, func(ctx context.Context) error {
+		return c.withToken(ctx, func(token *model.Token) error {
+			return c.send(ctx, c.executor.DoAPI, &util.Request{
+				Endpoint: c.config.UserInfoEndpoint,
+				Query:    url.Values{"access_token": []string{token.AccessToken}},
+			}, &resp)
+		})
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 
-	if resp.Ret != 0 {
-		return nil, fmt.Errorf("get user info failed with ret %d: %s", resp.Ret, resp.Msg)
-	}
-
 	return &resp.Data, nil
 }
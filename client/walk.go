@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// SkipDir 由 WalkFunc 返回时跳过当前文件夹，与 fs.SkipDir 相同
+var SkipDir = fs.SkipDir
+
+// SkipAll 由 WalkFunc 返回时结束遍历，与 fs.SkipAll 相同
+var SkipAll = fs.SkipAll
+
+// WalkFunc Walk 访问每个文件或文件夹时调用的函数
+//
+// p 为条目的路径，由各级文件夹标题拼接而成，如 "/项目/周报"；根文件夹为 "/"。doc.ParentID 为所在文件夹的ID。
+// 标题中的 "%" 和 "/" 分别转义为 "%25" 和 "%2F"，".." 等标题原样保留，不会被当作上级目录。
+// 列出文件夹失败时会以该文件夹的路径、文档（根文件夹为 nil）和错误再调用一次，
+// 返回 nil 或 SkipDir 时跳过该文件夹继续遍历。
+//
+// 返回 SkipDir 时：若 doc 是文件夹则不进入该文件夹，否则跳过所在文件夹中剩余的条目。
+// 返回 SkipAll 时结束遍历，Walk 返回 nil。返回其他错误时结束遍历，Walk 返回该错误。
+type WalkFunc func(p string, doc *model.Document, err error) error
+
+// WalkOption Walk 配置选项
+type WalkOption func(*walkOptions)
+
+type walkOptions struct {
+	maxDepth    int
+	concurrency int
+	callOpts    []CallOption
+}
+
+// WithMaxDepth 设置最大遍历深度，根文件夹的直接子项深度为1，深度达到 depth 的文件夹不再进入，0 表示不限制
+func WithMaxDepth(depth int) WalkOption {
+	return func(o *walkOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// WithWalkConcurrency 设置同时列出的文件夹数量上限，默认为1
+func WithWalkConcurrency(n int) WalkOption {
+	return func(o *walkOptions) {
+		if n > 0 {
+			o.concurrency = n
+		}
+	}
+}
+
+// WithWalkCallOptions 设置列出文件夹时每次 ListDocuments 调用使用的选项
+func WithWalkCallOptions(opts ...CallOption) WalkOption {
+	return func(o *walkOptions) {
+		o.callOpts = append(o.callOpts, opts...)
+	}
+}
+
+// Walk 从 root 文件夹开始递归遍历，对每个文件和文件夹调用 fn，root 为空时从根目录开始。
+//
+// 文件夹（Type 为 "folder"）按层次顺序逐个列出，并发数大于1时多个文件夹并行列出，访问顺序不确定，
+// 但 fn 不会被并发调用。同一文件夹在多处出现（如共享文件夹）时每处都会调用 fn，但只会进入一次，避免重复和循环。
+//
+// 可传入任意 TencentDocClient 实现，如 *Client 或 tdoctest.FakeClient。
+// ctx 被取消时尽快结束并返回 ctx.Err()。
+func Walk(ctx context.Context, c TencentDocClient, root string, fn WalkFunc, opts ...WalkOption) error {
+	o := walkOptions{concurrency: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if root == "" {
+		root = "/"
+	}
+
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &walker{
+		ctx:     listCtx,
+		cancel:  cancel,
+		client:  c,
+		fn:      fn,
+		opts:    o,
+		visited: map[string]bool{root: true},
+		queue:   []walkTask{{folderID: root, path: "/"}},
+		pending: 1,
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	var wg sync.WaitGroup
+	for range o.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work()
+		}()
+	}
+	wg.Wait()
+
+	if w.err != nil {
+		return w.err
+	}
+	if w.stopped {
+		return nil // SkipAll
+	}
+	return ctx.Err()
+}
+
+// walkTask 待列出的文件夹
+type walkTask struct {
+	folderID string
+	path     string
+	doc      *model.Document // 根文件夹为 nil
+	depth    int
+}
+
+type walker struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	client TencentDocClient
+	fn     WalkFunc
+	opts   walkOptions
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	visited map[string]bool // 已进入或已排队的文件夹ID
+	queue   []walkTask
+	pending int // 排队中和正在处理的文件夹数量
+	stopped bool
+	err     error
+}
+
+func (w *walker) work() {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.pending > 0 && !w.stopped {
+			w.cond.Wait()
+		}
+		if w.stopped || w.pending == 0 {
+			w.mu.Unlock()
+			return
+		}
+		task := w.queue[0]
+		w.queue = w.queue[1:]
+		w.mu.Unlock()
+
+		docs, err := w.list(task.folderID)
+
+		w.mu.Lock()
+		w.visit(task, docs, err)
+		w.pending--
+		if w.pending == 0 || w.stopped {
+			w.cond.Broadcast()
+		}
+		w.mu.Unlock()
+	}
+}
+
+// list 列出文件夹的全部直接子项
+func (w *walker) list(folderID string) ([]*model.Document, error) {
+	var docs []*model.Document
+	params := &model.ListParams{FolderID: folderID, ListType: constant.ListTypeFolder}
+	for doc, err := range AllDocuments(w.ctx, w.client, params, w.opts.callOpts...) {
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// visit 对文件夹的子项调用 fn 并将子文件夹加入队列，调用方需持有 w.mu
+func (w *walker) visit(task walkTask, docs []*model.Document, err error) {
+	if w.stopped {
+		return
+	}
+	if err != nil {
+		w.handle(w.fn(task.path, task.doc, err))
+		return
+	}
+
+	depth := task.depth + 1
+	for _, doc := range docs {
+		doc.ParentID = task.folderID
+		p := joinPath(task.path, doc.Title)
+		isFolder := doc.Type == constant.FileTypeFolder
+
+		ret := w.fn(p, doc, nil)
+		if errors.Is(ret, SkipDir) {
+			if isFolder {
+				continue
+			}
+			return
+		}
+		if w.handle(ret) {
+			return
+		}
+
+		if !isFolder || w.visited[doc.ID] || (w.opts.maxDepth > 0 && depth >= w.opts.maxDepth) {
+			continue
+		}
+		w.visited[doc.ID] = true
+		w.queue = append(w.queue, walkTask{folderID: doc.ID, path: p, doc: doc, depth: depth})
+		w.pending++
+		w.cond.Signal()
+	}
+}
+
+// pathEscaper 转义标题中的路径分隔符，先转义 "%" 使不同标题的转义结果不会相同
+var pathEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+// joinPath 将标题转义后拼接到文件夹路径后，不使用 path.Join 以免 ".." 等标题被清理
+func joinPath(dir, title string) string {
+	if dir == "/" {
+		return "/" + pathEscaper.Replace(title)
+	}
+	return dir + "/" + pathEscaper.Replace(title)
+}
+
+// handle 处理 fn 的返回值，需要结束遍历时返回 true，调用方需持有 w.mu
+func (w *walker) handle(err error) bool {
+	if err == nil || errors.Is(err, SkipDir) {
+		return false
+	}
+	w.stopped = true
+	if !errors.Is(err, SkipAll) {
+		w.err = err
+	}
+	w.cancel()
+	w.cond.Broadcast()
+	return true
+}
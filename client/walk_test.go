@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// folderClient 按文件夹ID返回预设的子项，并记录同时进行的列出请求数
+type folderClient struct {
+	TencentDocClient
+	folders map[string][]*model.Document
+	delay   time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	listed   []string
+}
+
+func (c *folderClient) ListDocuments(ctx context.Context, params *model.ListParams, _ ...CallOption) (*model.ListDocumentsResponse, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.peak = max(c.peak, c.inFlight)
+	c.listed = append(c.listed, params.FolderID)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var resp model.ListDocumentsResponse
+	resp.Data.List = c.folders[params.FolderID]
+	return &resp, nil
+}
+
+func folder(id, title string) *model.Document {
+	return &model.Document{ID: id, Title: title, Type: "folder"}
+}
+
+func file(id, title string) *model.Document {
+	return &model.Document{ID: id, Title: title, Type: "doc"}
+}
+
+// newFolderClient 返回如下结构，共享文件夹 s 同时出现在 a 和 b 中，且 s 中又包含 a：
+//
+//	/a/s/a
+//	/a/s/x
+//	/a/y
+//	/b/s
+//	/z
+func newFolderClient() *folderClient {
+	return &folderClient{folders: map[string][]*model.Document{
+		"/": {folder("a", "a"), folder("b", "b"), file("z", "z")},
+		"a": {folder("s", "s"), file("y", "y")},
+		"b": {folder("s", "s")},
+		"s": {folder("a", "a"), file("x", "x")},
+	}}
+}
+
+func TestWalkVisitsEachFolderOnce(t *testing.T) {
+	t.Parallel()
+
+	c := newFolderClient()
+
+	var paths []string
+	err := Walk(context.Background(), c, "", func(p string, doc *model.Document, err error) error {
+		if err != nil {
+			t.Fatalf("WalkFunc(%q) error = %v", p, err)
+		}
+		paths = append(paths, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	want := []string{"/a", "/b", "/z", "/a/s", "/a/y", "/b/s", "/a/s/a", "/a/s/x"}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("Walk() paths = %v, want %v", paths, want)
+	}
+	if !slices.Equal(c.listed, []string{"/", "a", "b", "s"}) {
+		t.Fatalf("listed folders = %v", c.listed)
+	}
+}
+
+func TestWalkSkipDirAndMaxDepth(t *testing.T) {
+	t.Parallel()
+
+	c := newFolderClient()
+
+	var paths []string
+	err := Walk(context.Background(), c, "/", func(p string, doc *model.Document, err error) error {
+		paths = append(paths, p)
+		if doc.ID == "b" {
+			return SkipDir
+		}
+		return nil
+	}, WithMaxDepth(2))
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	want := []string{"/a", "/b", "/z", "/a/s", "/a/y"}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("Walk() paths = %v, want %v", paths, want)
+	}
+	if !slices.Equal(c.listed, []string{"/", "a"}) {
+		t.Fatalf("listed folders = %v", c.listed)
+	}
+}
+
+func TestWalkStopsOnError(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+	for _, tt := range []struct {
+		ret  error
+		want error
+	}{
+		{ret: SkipAll, want: nil},
+		{ret: errStop, want: errStop},
+	} {
+		n := 0
+		err := Walk(context.Background(), newFolderClient(), "", func(string, *model.Document, error) error {
+			n++
+			return tt.ret
+		})
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Fatalf("Walk() error = %v, want %v", err, tt.want)
+		}
+		if n != 1 {
+			t.Fatalf("WalkFunc calls = %d, want 1", n)
+		}
+	}
+}
+
+func TestWalkBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	c := &folderClient{folders: map[string][]*model.Document{}, delay: 20 * time.Millisecond}
+	for _, id := range []string{"1", "2", "3", "4", "5", "6"} {
+		c.folders["/"] = append(c.folders["/"], folder(id, id))
+	}
+
+	var mu sync.Mutex
+	calls := 0
+	err := Walk(context.Background(), c, "", func(string, *model.Document, error) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return nil
+	}, WithWalkConcurrency(3))
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	if calls != 6 || len(c.listed) != 7 {
+		t.Fatalf("WalkFunc calls = %d, folders listed = %d", calls, len(c.listed))
+	}
+	if c.peak < 2 || c.peak > 3 {
+		t.Fatalf("peak concurrent listings = %d, want 2..3", c.peak)
+	}
+}
+
+func TestWalkReportsListErrorAndCancel(t *testing.T) {
+	t.Parallel()
+
+	c := &folderClient{folders: map[string][]*model.Document{"/": {folder("a", "a")}}, delay: time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var got error
+	err := Walk(ctx, c, "", func(p string, doc *model.Document, err error) error {
+		if p != "/" || doc != nil {
+			t.Fatalf("WalkFunc(%q, %v) for list error", p, doc)
+		}
+		got = err
+		return nil
+	})
+	if !errors.Is(got, context.DeadlineExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WalkFunc error = %v, Walk() error = %v; want DeadlineExceeded", got, err)
+	}
+}
+
+func TestWalkEscapesTitles(t *testing.T) {
+	t.Parallel()
+
+	c := &folderClient{folders: map[string][]*model.Document{
+		"/": {folder("a", "a/b"), file("up", ".."), file("pct", "50%")},
+		"a": {file("x", "a/../b")},
+	}}
+
+	var paths []string
+	err := Walk(context.Background(), c, "", func(p string, doc *model.Document, err error) error {
+		paths = append(paths, p)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	want := []string{"/a%2Fb", "/..", "/50%25", "/a%2Fb/a%2F..%2Fb"}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("Walk() paths = %v, want %v", paths, want)
+	}
+}
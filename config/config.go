@@ -2,10 +2,18 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/chinahtl/tencent-doc-sdk/constant"
 	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/store"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+	"github.com/chinahtl/tencent-doc-sdk/util"
 )
 
 // Config 客户端配置
@@ -17,6 +25,30 @@ type Config struct {
 	Timeout      time.Duration
 	InitialToken *model.Token      // 新增初始 Token 字段
 	Transport    http.RoundTripper // 自定义 HTTP Transport
+	// RefreshBefore 访问令牌到期前多久自动刷新，为0时仅在接口返回令牌过期后刷新
+	RefreshBefore time.Duration
+	// TokenStore 令牌存储，换取或刷新令牌后自动写回
+	TokenStore store.TokenStore
+	// TokenKeyring 令牌加密密钥环，设置后 TokenStore 中的令牌会被加密保存
+	TokenKeyring *store.Keyring
+	// RetryPolicy 请求重试策略，为空时不重试
+	RetryPolicy *util.RetryPolicy
+	// RateLimiter 客户端限流器，为空时不限流
+	RateLimiter *util.RateLimiter
+	// Interceptors 请求拦截器，按注册顺序执行
+	Interceptors []util.Interceptor
+	// Logger 结构化日志，为空时不记录日志，令牌等敏感字段会被脱敏
+	Logger *slog.Logger
+	// Tracer 链路追踪，为空时不创建 span
+	Tracer telemetry.Tracer
+	// Metrics 指标采集，默认为 telemetry.NopMetrics
+	Metrics telemetry.Metrics
+
+	// 服务端点，默认指向 docs.qq.com，可在测试或私有化部署时覆盖
+	AuthEndpoint     string // 授权端点
+	TokenEndpoint    string // Token端点
+	APIEndpoint      string // OpenAPI 端点，各接口地址由此派生
+	UserInfoEndpoint string // 用户信息端点
 }
 
 // Option 定义配置选项函数类型
@@ -25,8 +57,33 @@ type Option func(*Config)
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
-		Timeout: 30 * time.Second,
+		Timeout:          30 * time.Second,
+		RefreshBefore:    5 * time.Minute,
+		Metrics:          telemetry.NopMetrics{},
+		AuthEndpoint:     constant.AuthEndpoint,
+		TokenEndpoint:    constant.TokenEndpoint,
+		APIEndpoint:      constant.APIEndpoint,
+		UserInfoEndpoint: constant.UserInfoEndpoint,
+	}
+}
+
+// Validate 校验配置，服务端点必须是包含协议和主机的绝对地址
+func (c *Config) Validate() error {
+	for _, endpoint := range []struct{ name, value string }{
+		{"AuthEndpoint", c.AuthEndpoint},
+		{"TokenEndpoint", c.TokenEndpoint},
+		{"APIEndpoint", c.APIEndpoint},
+		{"UserInfoEndpoint", c.UserInfoEndpoint},
+	} {
+		u, err := url.Parse(endpoint.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", endpoint.name, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid %s %q: scheme and host are required", endpoint.name, endpoint.value)
+		}
 	}
+	return nil
 }
 
 // WithClientID 设置客户端ID
@@ -77,3 +134,110 @@ func WithHttpTransport(transport http.RoundTripper) Option {
 		c.Transport = transport
 	}
 }
+
+// WithRefreshBefore 设置访问令牌到期前的自动刷新提前量
+func WithRefreshBefore(d time.Duration) Option {
+	return func(c *Config) {
+		c.RefreshBefore = d
+	}
+}
+
+// WithTokenStore 设置令牌存储
+func WithTokenStore(s store.TokenStore) Option {
+	return func(c *Config) {
+		c.TokenStore = s
+	}
+}
+
+// WithTokenEncryption 设置令牌加密密钥环，可通过 store.KeyringFromEnv 从环境变量读取
+func WithTokenEncryption(keys *store.Keyring) Option {
+	return func(c *Config) {
+		c.TokenKeyring = keys
+	}
+}
+
+// WithRetryPolicy 设置请求重试策略，可使用 util.DefaultRetryPolicy 作为基础进行调整
+func WithRetryPolicy(policy *util.RetryPolicy) Option {
+	return func(c *Config) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithRateLimiter 设置客户端限流器，多个客户端传入同一实例时共享应用维度的额度
+func WithRateLimiter(limiter *util.RateLimiter) Option {
+	return func(c *Config) {
+		c.RateLimiter = limiter
+	}
+}
+
+// WithInterceptors 追加请求拦截器，可用于添加请求头、记录日志等横切逻辑
+func WithInterceptors(interceptors ...util.Interceptor) Option {
+	return func(c *Config) {
+		c.Interceptors = append(c.Interceptors, interceptors...)
+	}
+}
+
+// WithLogger 设置结构化日志，每次 HTTP 请求（含重试）记录一条日志
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithTracer 设置链路追踪，每次 SDK 操作创建一个 span，并将链路上下文注入请求头
+func WithTracer(tracer telemetry.Tracer) Option {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithMetrics 设置指标采集，metrics 为空时不记录指标
+func WithMetrics(metrics telemetry.Metrics) Option {
+	return func(c *Config) {
+		if metrics == nil {
+			metrics = telemetry.NopMetrics{}
+		}
+		c.Metrics = metrics
+	}
+}
+
+// WithBaseURL 设置服务地址，授权、Token、OpenAPI 和用户信息端点均由此派生
+//
+// 例如 WithBaseURL("https://docs.example.com") 会将 OpenAPI 端点设置为 https://docs.example.com/openapi。
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		baseURL = strings.TrimRight(baseURL, "/")
+		c.AuthEndpoint = baseURL + constant.AuthPath
+		c.TokenEndpoint = baseURL + constant.TokenPath
+		c.APIEndpoint = baseURL + constant.APIPath
+		c.UserInfoEndpoint = baseURL + constant.UserInfoPath
+	}
+}
+
+// WithAuthEndpoint 设置授权端点
+func WithAuthEndpoint(endpoint string) Option {
+	return func(c *Config) {
+		c.AuthEndpoint = endpoint
+	}
+}
+
+// WithTokenEndpoint 设置Token端点
+func WithTokenEndpoint(endpoint string) Option {
+	return func(c *Config) {
+		c.TokenEndpoint = endpoint
+	}
+}
+
+// WithAPIEndpoint 设置 OpenAPI 端点
+func WithAPIEndpoint(endpoint string) Option {
+	return func(c *Config) {
+		c.APIEndpoint = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// WithUserInfoEndpoint 设置用户信息端点
+func WithUserInfoEndpoint(endpoint string) Option {
+	return func(c *Config) {
+		c.UserInfoEndpoint = endpoint
+	}
+}
@@ -1,14 +1,25 @@
 package constant
 
 const (
+	// BaseURL 腾讯文档服务地址
+	BaseURL = "https://docs.qq.com"
+	// AuthPath 授权端点路径
+	AuthPath = "/oauth/v2/authorize"
+	// TokenPath Token端点路径
+	TokenPath = "/oauth/v2/token"
+	// APIPath API端点路径
+	APIPath = "/openapi"
+	// UserInfoPath 用户信息端点路径
+	UserInfoPath = "/oauth/v2/userinfo"
+
 	// AuthEndpoint 授权端点
-	AuthEndpoint = "https://docs.qq.com/oauth/v2/authorize"
+	AuthEndpoint = BaseURL + AuthPath
 	// TokenEndpoint Token端点
-	TokenEndpoint = "https://docs.qq.com/oauth/v2/token"
+	TokenEndpoint = BaseURL + TokenPath
 	// APIEndpoint API端点
-	APIEndpoint = "https://docs.qq.com/openapi"
-	// UserInfoEndpoint API端点
-	UserInfoEndpoint = "https://docs.qq.com/oauth/v2/userinfo"
+	APIEndpoint = BaseURL + APIPath
+	// UserInfoEndpoint 用户信息端点
+	UserInfoEndpoint = BaseURL + UserInfoPath
 	// AllScope 全部权限
 	AllScope = "all"
 )
@@ -22,12 +33,29 @@ const (
 	SortTypeTime   = "time"
 	SortTypeName   = "name"
 
-	FileTypeDoc   = "doc"
-	FileTypeSheet = "sheet"
-	FileTypeSlide = "slide"
+	FileTypeFolder = "folder"
+	FileTypeDoc    = "doc"
+	FileTypeSheet  = "sheet"
+	FileTypeSlide  = "slide"
 
 	ExportTypePDF  = "pdf"
 	ExportTypeDocx = "docx"
 	ExportTypeXlsx = "xlsx"
 	ExportTypePptx = "pptx"
 )
+
+// OpenAPI 通用返回码
+const (
+	// RetInvalidParams 请求参数错误
+	RetInvalidParams = 400001
+	// RetPermissionDenied 无权限访问
+	RetPermissionDenied = 400003
+	// RetNotFound 文件或资源不存在
+	RetNotFound = 400004
+	// RetAccessTokenInvalid access token无效
+	RetAccessTokenInvalid = 400006
+	// RetAccessTokenExpired access token已过期
+	RetAccessTokenExpired = 400007
+	// RetRateLimited 请求频率超过限制
+	RetRateLimited = 400009
+)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -112,44 +113,28 @@ func searchDocuments(docClient *client.Client, searchKey string) {
 // 导出文档
 func exportDocument(docClient *client.Client, docID string) {
 	req := &model.ExportRequest{}
-	resp, err := docClient.ExportDocument(context.Background(), docID, req)
+	result, err := docClient.ExportAndWait(context.Background(), docID, req,
+		client.WithPollInterval(time.Second, 5*time.Second),
+		client.WithExportTimeout(3*time.Minute),
+		client.WithProgressFunc(func(progress int) {
+			fmt.Printf("当前进度: %d%%\n", progress)
+		}),
+	)
+	if errors.Is(err, client.ErrExportTimeout) {
+		fmt.Println("导出超时，请稍后重试")
+		return
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Println(resp)
 
-	progressResp := pollExportProgress(docClient, docID, resp.Data.OperationID)
-	if progressResp.Data.Progress == 100 {
-		downloadExportedFile(progressResp.Data.URL)
-	} else {
-		fmt.Println("导出超时，请稍后重试")
-	}
-}
-
-// 轮询导出进度
-func pollExportProgress(docClient *client.Client, docID, operationID string) *model.ExportProgressResponse {
-	var progressResp *model.ExportProgressResponse
-	for i := 0; i < 100; i++ {
-		time.Sleep(2 * time.Second)
-
-		var err error
-		progressResp, err = docClient.GetExportProgress(context.Background(), docID, operationID)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		fmt.Printf("当前进度: %d%%\n", progressResp.Data.Progress)
-		if progressResp.Data.Progress == 100 {
-			break
-		}
-	}
-	return progressResp
+	downloadExportedFile(docClient, result.URL)
 }
 
 // 下载导出的文件
-func downloadExportedFile(url string) {
+func downloadExportedFile(docClient *client.Client, url string) {
 	fmt.Println("导出完成，下载URL:", url)
-	cos, err := util.DownloadFromCOS(url, "./tmp/cos")
+	cos, err := docClient.DownloadFromCOS(context.Background(), url, "./tmp/cos")
 	if err != nil {
 		log.Fatal(err)
 	}
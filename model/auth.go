@@ -8,6 +8,7 @@ type Token struct {
 	TokenType    string `json:"token_type"`
 	UserID       string `json:"user_id"`
 	Scope        string `json:"scope"`
+	ExpiresAt    int64  `json:"expires_at,omitempty"` // 过期时间戳(秒级)，由SDK根据ExpiresIn计算
 }
 
 // TokenResponse Token响应
@@ -18,6 +18,7 @@ type Document struct {
 	Starred        bool   `json:"starred,omitempty"`
 	Pinned         bool   `json:"pinned,omitempty"`
 	IsCollaborated bool   `json:"isCollaborated"`
+	ParentID       string `json:"parentID,omitempty"` // 所在文件夹ID，接口不返回，由 client.Walk 填充
 }
 
 // ListDocumentsResponse 文档列表响应
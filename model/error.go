@@ -0,0 +1,89 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+)
+
+// 常见错误类型，可配合 errors.Is 判断 APIError 的类别
+var (
+	ErrTokenExpired     = errors.New("access token expired")
+	ErrInvalidToken     = errors.New("invalid access token")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrNotFound         = errors.New("not found")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrInvalidParams    = errors.New("invalid params")
+)
+
+// APIError 接口调用失败时返回的错误
+//
+// HTTP 状态码非200或响应中 ret 非0时返回，可通过 errors.As 获取详细信息：
+//
+//	var apiErr *model.APIError
+//	if errors.As(err, &apiErr) {
+//	    log.Println(apiErr.Ret, apiErr.RequestID)
+//	}
+type APIError struct {
+	StatusCode int    // HTTP 状态码
+	Ret        int    // 响应中的返回码
+	Msg        string // 响应中的错误信息，无法解析时为响应体内容
+	Endpoint   string // 请求地址，不含查询参数
+	RequestID  string // 服务端返回的请求ID
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	var b strings.Builder
+	switch {
+	case e.Ret != 0:
+		fmt.Fprintf(&b, "api error: %s (ret=%d", e.Msg, e.Ret)
+		if e.StatusCode != 0 && e.StatusCode != http.StatusOK {
+			fmt.Fprintf(&b, ", status=%d", e.StatusCode)
+		}
+	case e.StatusCode == http.StatusOK:
+		// 状态码正常但响应内容无效，如令牌接口未返回 access_token
+		fmt.Fprintf(&b, "api error: %s (endpoint=%s", e.Msg, e.Endpoint)
+	default:
+		fmt.Fprintf(&b, "unexpected status code: %d, body: %s (", e.StatusCode, e.Msg)
+		b.WriteString("endpoint=" + e.Endpoint)
+	}
+	if e.RequestID != "" {
+		b.WriteString(", request_id=" + e.RequestID)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// Is 根据返回码和 HTTP 状态码匹配常见错误类型
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrTokenExpired:
+		return e.Ret == constant.RetAccessTokenExpired
+	case ErrInvalidToken:
+		return e.Ret == constant.RetAccessTokenInvalid || e.StatusCode == http.StatusUnauthorized
+	case ErrPermissionDenied:
+		return e.Ret == constant.RetPermissionDenied || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.Ret == constant.RetNotFound || e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.Ret == constant.RetRateLimited || e.StatusCode == http.StatusTooManyRequests
+	case ErrInvalidParams:
+		return e.Ret == constant.RetInvalidParams || e.StatusCode == http.StatusBadRequest
+	}
+	return false
+}
+
+// RequestIDFromError 返回 err 中 *APIError 的服务端请求ID，不存在时返回空字符串
+//
+// 向腾讯文档反馈问题时可附带该请求ID。
+func RequestIDFromError(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RequestID
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package snapshot
+
+// Change 一个文件的变更，Old 和 New 分别为变更前后的快照条目
+type Change struct {
+	ID  string `json:"id"`
+	Old *Entry `json:"old,omitempty"` // 新增的文件为 nil
+	New *Entry `json:"new,omitempty"` // 删除的文件为 nil
+}
+
+// Diff 两个快照之间的差异，各分类按路径排序
+//
+// 同一文件可能同时出现在多个分类中，如移动后又被修改。
+type Diff struct {
+	Added    []*Change `json:"added"`    // 新增的文件
+	Removed  []*Change `json:"removed"`  // 删除的文件
+	Renamed  []*Change `json:"renamed"`  // 标题变化的文件
+	Moved    []*Change `json:"moved"`    // 所在文件夹变化的文件
+	Modified []*Change `json:"modified"` // 最后修改时间变化的文件
+}
+
+// Empty 判断两个快照之间是否没有差异
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Renamed) == 0 &&
+		len(d.Moved) == 0 && len(d.Modified) == 0
+}
+
+// Compare 按文件ID比较两个快照，返回从 old 到 cur 的差异
+func Compare(old, cur *Snapshot) *Diff {
+	diff := &Diff{
+		Added:    []*Change{},
+		Removed:  []*Change{},
+		Renamed:  []*Change{},
+		Moved:    []*Change{},
+		Modified: []*Change{},
+	}
+
+	before := make(map[string]*Entry, len(old.Entries))
+	for _, e := range old.Entries {
+		before[e.ID] = e
+	}
+	after := make(map[string]*Entry, len(cur.Entries))
+	for _, e := range cur.Entries {
+		after[e.ID] = e
+	}
+
+	// 快照条目已按路径排序，按顺序遍历即可得到有序的结果
+	for _, e := range cur.Entries {
+		prev, ok := before[e.ID]
+		if !ok {
+			diff.Added = append(diff.Added, &Change{ID: e.ID, New: e})
+			continue
+		}
+		change := &Change{ID: e.ID, Old: prev, New: e}
+		if prev.Title != e.Title {
+			diff.Renamed = append(diff.Renamed, change)
+		}
+		if prev.ParentID != e.ParentID {
+			diff.Moved = append(diff.Moved, change)
+		}
+		if prev.LastModifyTime != e.LastModifyTime {
+			diff.Modified = append(diff.Modified, change)
+		}
+	}
+	for _, e := range old.Entries {
+		if _, ok := after[e.ID]; !ok {
+			diff.Removed = append(diff.Removed, &Change{ID: e.ID, Old: e})
+		}
+	}
+	return diff
+}
@@ -0,0 +1,94 @@
+// Package snapshot 提供文件夹树快照及快照间差异计算，用于检测共享文件夹中的变更。
+//
+// 快照可序列化为 JSON 保存，之后与新的快照比较：
+//
+//	old := loadYesterday()
+//	cur, err := snapshot.Capture(ctx, docClient, folderID)
+//	diff := snapshot.Compare(old, cur)
+//	json.NewEncoder(os.Stdout).Encode(diff)
+package snapshot
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/client"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// Snapshot 某一时刻文件夹树的快照
+type Snapshot struct {
+	RootID     string    `json:"rootID"`     // 快照的根文件夹ID
+	CapturedAt time.Time `json:"capturedAt"` // 快照时间
+	Entries    []*Entry  `json:"entries"`    // 文件和文件夹，按路径排序
+}
+
+// Entry 快照中的文件或文件夹
+type Entry struct {
+	ID             string `json:"id"`
+	Title          string `json:"title"`
+	Type           string `json:"type"`
+	ParentID       string `json:"parentID"` // 所在文件夹ID
+	Path           string `json:"path"`     // 相对根文件夹的路径，如 "/项目/周报"
+	LastModifyTime int64  `json:"lastModifyTime"`
+	OwnerName      string `json:"ownerName"`
+}
+
+// Capture 使用 client.Walk 遍历 root 文件夹并生成快照，root 为空时从根目录开始。
+//
+// opts 会传给 client.Walk，可用于设置并发数等，但不应限制深度，否则更深的文件在比较时会被视为删除。
+// 任一文件夹列出失败时返回错误，不会返回不完整的快照。
+// 同一文件在多处出现（如共享文件夹）时只记录路径最小的一处。
+func Capture(ctx context.Context, c client.TencentDocClient, root string, opts ...client.WalkOption) (*Snapshot, error) {
+	if root == "" {
+		root = "/"
+	}
+	snap := &Snapshot{RootID: root, CapturedAt: time.Now()}
+
+	entries := make(map[string]*Entry)
+	err := client.Walk(ctx, c, root, func(p string, doc *model.Document, err error) error {
+		if err != nil {
+			return err
+		}
+		if e, ok := entries[doc.ID]; ok && e.Path <= p {
+			return nil
+		}
+		entries[doc.ID] = &Entry{
+			ID:             doc.ID,
+			Title:          doc.Title,
+			Type:           doc.Type,
+			ParentID:       doc.ParentID,
+			Path:           p,
+			LastModifyTime: doc.LastModifyTime,
+			OwnerName:      doc.OwnerName,
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		snap.Entries = append(snap.Entries, e)
+	}
+	sortEntries(snap.Entries)
+	return snap, nil
+}
+
+// Entry 按ID查找快照中的文件
+func (s *Snapshot) Entry(id string) (*Entry, bool) {
+	for _, e := range s.Entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+func sortEntries(entries []*Entry) {
+	slices.SortFunc(entries, func(a, b *Entry) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.ID, b.ID))
+	})
+}
@@ -0,0 +1,96 @@
+package snapshot_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/snapshot"
+	"github.com/chinahtl/tencent-doc-sdk/tdoctest"
+)
+
+func ids(changes []*snapshot.Change) string {
+	var s []string
+	for _, c := range changes {
+		s = append(s, c.ID)
+	}
+	return strings.Join(s, ",")
+}
+
+func TestCaptureAndCompare(t *testing.T) {
+	t.Parallel()
+
+	fake := tdoctest.NewFakeClient(
+		tdoctest.File{ID: "proj", Title: "项目", Type: "folder", CreateTime: 1},
+		tdoctest.File{ID: "arch", Title: "归档", Type: "folder", CreateTime: 1},
+		tdoctest.File{ID: "d1", ParentID: "proj", Title: "周报", Type: "doc", CreateTime: 10},
+		tdoctest.File{ID: "d2", ParentID: "proj", Title: "预算", Type: "sheet", CreateTime: 10},
+		tdoctest.File{ID: "d3", ParentID: "proj", Title: "旧方案", Type: "doc", CreateTime: 10},
+		tdoctest.File{ID: "d4", ParentID: "proj", Title: "计划", Type: "doc", CreateTime: 10},
+	)
+	ctx := context.Background()
+
+	old, err := snapshot.Capture(ctx, fake, "")
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	if len(old.Entries) != 6 {
+		t.Fatalf("Capture() = %d entries, want 6", len(old.Entries))
+	}
+	if e, ok := old.Entry("d1"); !ok || e.Path != "/项目/周报" || e.ParentID != "proj" {
+		t.Fatalf("Entry(d1) = %+v, %v", e, ok)
+	}
+
+	// 模拟一天内的变更
+	fake.AddFile(tdoctest.File{ID: "d1", ParentID: "proj", Title: "周报 v2", Type: "doc", CreateTime: 10})
+	fake.AddFile(tdoctest.File{ID: "d2", ParentID: "arch", Title: "预算", Type: "sheet", CreateTime: 10})
+	fake.AddFile(tdoctest.File{ID: "d4", ParentID: "proj", Title: "计划", Type: "doc", CreateTime: 10, LastModifyTime: 20})
+	fake.RemoveFile("d3")
+	fake.AddFile(tdoctest.File{ID: "d5", ParentID: "proj", Title: "新方案", Type: "doc", CreateTime: 30})
+
+	// 经过 JSON 往返，与保存到文件后再加载的快照一致
+	data, err := json.Marshal(old)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var loaded snapshot.Snapshot
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	cur, err := snapshot.Capture(ctx, fake, "")
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	diff := snapshot.Compare(&loaded, cur)
+
+	for name, tt := range map[string]struct{ got, want string }{
+		"added":    {ids(diff.Added), "d5"},
+		"removed":  {ids(diff.Removed), "d3"},
+		"renamed":  {ids(diff.Renamed), "d1"},
+		"moved":    {ids(diff.Moved), "d2"},
+		"modified": {ids(diff.Modified), "d4"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("diff.%s = %q, want %q", name, tt.got, tt.want)
+		}
+	}
+	if diff.Moved[0].Old.Path != "/项目/预算" || diff.Moved[0].New.Path != "/归档/预算" {
+		t.Errorf("moved paths = %q -> %q", diff.Moved[0].Old.Path, diff.Moved[0].New.Path)
+	}
+
+	if !snapshot.Compare(cur, cur).Empty() {
+		t.Error("Compare(cur, cur) is not empty")
+	}
+
+	out, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatalf("Marshal(diff) error = %v", err)
+	}
+	for _, key := range []string{`"added"`, `"removed"`, `"renamed"`, `"moved"`, `"modified"`} {
+		if !strings.Contains(string(out), key) {
+			t.Errorf("diff JSON %s missing %s", out, key)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// DefaultKeyEnv 默认读取加密密钥的环境变量名
+const DefaultKeyEnv = "TENCENT_DOC_TOKEN_KEYS"
+
+// envelopePrefix 加密字段的前缀，完整格式为 tdenc:v1:<keyID>:<base64(nonce|密文)>
+const envelopePrefix = "tdenc:v1:"
+
+var (
+	// ErrDecrypt 令牌解密失败，密文被篡改或密钥不匹配
+	ErrDecrypt = errors.New("token decryption failed")
+	// ErrUnknownKey 密文使用的密钥ID不在密钥环中
+	ErrUnknownKey = errors.New("unknown token encryption key")
+	// ErrNotEncrypted 存储中读到了未加密的令牌
+	ErrNotEncrypted = errors.New("token is not encrypted")
+)
+
+// Keyring AES-GCM 密钥环
+//
+// 新数据始终使用主密钥加密，其余密钥仅用于解密旧数据，以支持密钥轮换。
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring 创建密钥环
+//
+// keys 为密钥ID到密钥的映射，密钥长度须为 16、24 或 32 字节；primary 为用于加密的密钥ID。
+// 密钥ID不能为空且不能包含冒号。
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q not found in keys", primary)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id: %q", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	return &Keyring{primary: primary, aeads: aeads}, nil
+}
+
+// ParseKeyring 解析 "id1:base64key1,id2:base64key2" 格式的密钥配置，第一个密钥为主密钥
+func ParseKeyring(spec string) (*Keyring, error) {
+	var primary string
+	keys := make(map[string][]byte)
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid key spec %q: want id:base64key", item)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("duplicate key id: %q", id)
+		}
+
+		keys[id] = key
+		if primary == "" {
+			primary = id
+		}
+	}
+
+	if primary == "" {
+		return nil, fmt.Errorf("no encryption key configured")
+	}
+	return NewKeyring(primary, keys)
+}
+
+// KeyringFromEnv 从环境变量读取密钥配置，name 为空时使用 DefaultKeyEnv，格式同 ParseKeyring
+func KeyringFromEnv(name string) (*Keyring, error) {
+	if name == "" {
+		name = DefaultKeyEnv
+	}
+
+	spec, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s not set", name)
+	}
+	return ParseKeyring(spec)
+}
+
+func (k *Keyring) seal(plaintext, aad string) (string, error) {
+	aead := k.aeads[k.primary]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce failed: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(aad))
+	return envelopePrefix + k.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (k *Keyring) open(envelope, aad string) (string, error) {
+	rest, ok := strings.CutPrefix(envelope, envelopePrefix)
+	if !ok {
+		return "", ErrNotEncrypted
+	}
+
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrDecrypt
+	}
+	aead, ok := k.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(aad))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// EncryptedStore 为任意 TokenStore 增加字段级加密
+//
+// AccessToken 和 RefreshToken 在写入底层存储前使用 AES-GCM 加密，
+// 并以 OpenID 和字段名作为附加认证数据，防止密文被篡改或在用户、字段之间替换。
+// 其余字段（过期时间、UserID 等）保持明文，便于底层存储检索。
+type EncryptedStore struct {
+	inner TokenStore
+	keys  *Keyring
+}
+
+// 确保 EncryptedStore 实现 TokenStore 接口
+var _ TokenStore = (*EncryptedStore)(nil)
+
+// NewEncryptedStore 创建加密令牌存储
+func NewEncryptedStore(inner TokenStore, keys *Keyring) *EncryptedStore {
+	return &EncryptedStore{inner: inner, keys: keys}
+}
+
+// Load 读取并解密令牌
+//
+// 底层存储中的令牌未加密时返回 ErrNotEncrypted，密文校验失败时返回 ErrDecrypt。
+func (s *EncryptedStore) Load(ctx context.Context, openID string) (*model.Token, error) {
+	token, err := s.inner.Load(ctx, openID)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken, err = s.openField(token.AccessToken, openID, "access_token"); err != nil {
+		return nil, err
+	}
+	if token.RefreshToken, err = s.openField(token.RefreshToken, openID, "refresh_token"); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Save 加密并保存令牌
+func (s *EncryptedStore) Save(ctx context.Context, openID string, token *model.Token) error {
+	encrypted := cloneToken(token)
+
+	var err error
+	if encrypted.AccessToken, err = s.sealField(token.AccessToken, openID, "access_token"); err != nil {
+		return err
+	}
+	if encrypted.RefreshToken, err = s.sealField(token.RefreshToken, openID, "refresh_token"); err != nil {
+		return err
+	}
+
+	return s.inner.Save(ctx, openID, encrypted)
+}
+
+// Delete 删除令牌
+func (s *EncryptedStore) Delete(ctx context.Context, openID string) error {
+	return s.inner.Delete(ctx, openID)
+}
+
+// Reencrypt 使用当前主密钥重新加密指定用户的令牌，用于密钥轮换后迁移旧数据
+func (s *EncryptedStore) Reencrypt(ctx context.Context, openID string) error {
+	token, err := s.Load(ctx, openID)
+	if err != nil {
+		return err
+	}
+	return s.Save(ctx, openID, token)
+}
+
+func (s *EncryptedStore) sealField(value, openID, field string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	sealed, err := s.keys.seal(value, openID+"\x00"+field)
+	if err != nil {
+		return "", fmt.Errorf("encrypt %s failed: %w", field, err)
+	}
+	return sealed, nil
+}
+
+func (s *EncryptedStore) openField(value, openID, field string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	plaintext, err := s.keys.open(value, openID+"\x00"+field)
+	if err != nil {
+		return "", fmt.Errorf("decrypt %s failed: %w", field, err)
+	}
+	return plaintext, nil
+}
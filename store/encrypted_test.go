@@ -0,0 +1,172 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+func testKeyring(t *testing.T, primary string, ids ...string) *Keyring {
+	t.Helper()
+
+	keys := make(map[string][]byte)
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+	k, err := NewKeyring(primary, keys)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	return k
+}
+
+func TestEncryptedStore(t *testing.T) {
+	t.Parallel()
+	testTokenStore(t, NewEncryptedStore(NewMemoryStore(), testKeyring(t, "k1", "k1")))
+}
+
+func TestEncryptedStoreNeverWritesPlaintext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	inner := NewMemoryStore()
+	s := NewEncryptedStore(inner, testKeyring(t, "k1", "k1"))
+
+	if err := s.Save(ctx, "u1", &model.Token{AccessToken: "secret-access", RefreshToken: "secret-refresh"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, _ := inner.Load(ctx, "u1")
+	for _, v := range []string{raw.AccessToken, raw.RefreshToken} {
+		if strings.Contains(v, "secret") || !strings.HasPrefix(v, "tdenc:v1:k1:") {
+			t.Fatalf("stored value not encrypted: %q", v)
+		}
+	}
+}
+
+func TestEncryptedStoreDetectsTampering(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	flipByte := func(v string) string {
+		i := strings.LastIndex(v, ":")
+		sealed, _ := base64.StdEncoding.DecodeString(v[i+1:])
+		sealed[len(sealed)-1] ^= 0x01
+		return v[:i+1] + base64.StdEncoding.EncodeToString(sealed)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(raw *model.Token)
+		want   error
+	}{
+		{
+			name:   "flipped ciphertext",
+			tamper: func(raw *model.Token) { raw.AccessToken = flipByte(raw.AccessToken) },
+			want:   ErrDecrypt,
+		},
+		{
+			name:   "swapped fields",
+			tamper: func(raw *model.Token) { raw.AccessToken, raw.RefreshToken = raw.RefreshToken, raw.AccessToken },
+			want:   ErrDecrypt,
+		},
+		{
+			name:   "plaintext",
+			tamper: func(raw *model.Token) { raw.RefreshToken = "plain" },
+			want:   ErrNotEncrypted,
+		},
+		{
+			name:   "unknown key",
+			tamper: func(raw *model.Token) { raw.AccessToken = strings.Replace(raw.AccessToken, ":k1:", ":k9:", 1) },
+			want:   ErrUnknownKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := NewMemoryStore()
+			s := NewEncryptedStore(inner, testKeyring(t, "k1", "k1"))
+			if err := s.Save(ctx, "u1", &model.Token{AccessToken: "a", RefreshToken: "r"}); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			raw, _ := inner.Load(ctx, "u1")
+			tt.tamper(raw)
+			_ = inner.Save(ctx, "u1", raw)
+
+			if _, err := s.Load(ctx, "u1"); !errors.Is(err, tt.want) {
+				t.Fatalf("Load() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptedStoreRejectsTokenMovedToAnotherUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	inner := NewMemoryStore()
+	s := NewEncryptedStore(inner, testKeyring(t, "k1", "k1"))
+	if err := s.Save(ctx, "u1", &model.Token{AccessToken: "a", RefreshToken: "r"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, _ := inner.Load(ctx, "u1")
+	_ = inner.Save(ctx, "u2", raw)
+
+	if _, err := s.Load(ctx, "u2"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("Load() error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestEncryptedStoreKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	inner := NewMemoryStore()
+	old := NewEncryptedStore(inner, testKeyring(t, "k1", "k1"))
+	if err := old.Save(ctx, "u1", &model.Token{AccessToken: "a", RefreshToken: "r"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	rotated := NewEncryptedStore(inner, testKeyring(t, "k2", "k1", "k2"))
+	token, err := rotated.Load(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Load() with rotated keyring error = %v", err)
+	}
+	if token.AccessToken != "a" || token.RefreshToken != "r" {
+		t.Fatalf("Load() = %+v", token)
+	}
+
+	if err := rotated.Reencrypt(ctx, "u1"); err != nil {
+		t.Fatalf("Reencrypt() error = %v", err)
+	}
+	raw, _ := inner.Load(ctx, "u1")
+	if !strings.HasPrefix(raw.AccessToken, "tdenc:v1:k2:") {
+		t.Fatalf("Reencrypt() kept old key: %q", raw.AccessToken)
+	}
+}
+
+func TestParseKeyring(t *testing.T) {
+	t.Parallel()
+
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	k, err := ParseKeyring("new:" + key + ", old:" + key)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	if k.primary != "new" || len(k.aeads) != 2 {
+		t.Fatalf("ParseKeyring() primary = %q, keys = %d", k.primary, len(k.aeads))
+	}
+
+	for _, spec := range []string{"", "nokey", "k1:not-base64!", "k1:" + base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := ParseKeyring(spec); err == nil {
+			t.Errorf("ParseKeyring(%q) error = nil, want error", spec)
+		}
+	}
+}
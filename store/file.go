@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// FileStore 基于 JSON 文件的令牌存储
+//
+// 所有用户的令牌保存在同一个文件中，每次写入都先写临时文件再重命名，
+// 保证进程崩溃时文件内容不会损坏。文件权限为 0600。
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// 确保 FileStore 实现 TokenStore 接口
+var _ TokenStore = (*FileStore)(nil)
+
+// NewFileStore 创建文件令牌存储，path 所在目录不存在时会在首次写入时创建
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load 读取令牌
+func (s *FileStore) Load(_ context.Context, openID string) (*model.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := tokens[openID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return token, nil
+}
+
+// Save 保存令牌
+func (s *FileStore) Save(_ context.Context, openID string, token *model.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[openID] = cloneToken(token)
+
+	return s.write(tokens)
+}
+
+// Delete 删除令牌，令牌不存在时不返回错误
+func (s *FileStore) Delete(_ context.Context, openID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[openID]; !ok {
+		return nil
+	}
+	delete(tokens, openID)
+
+	return s.write(tokens)
+}
+
+func (s *FileStore) read() (map[string]*model.Token, error) {
+	tokens := make(map[string]*model.Token)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read token file failed: %w", err)
+	}
+	if len(data) == 0 {
+		return tokens, nil
+	}
+
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("decode token file failed: %w", err)
+	}
+	return tokens, nil
+}
+
+func (s *FileStore) write(tokens map[string]*model.Token) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode token file failed: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create token dir failed: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file failed: %w", err)
+	}
+	// 重命名成功后临时文件已不存在，Remove 只在失败路径上生效
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file failed: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file failed: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replace token file failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// MemoryStore 基于内存的令牌存储，进程重启后数据丢失，适用于测试和单机场景
+type MemoryStore struct {
+	mu     sync.RWMutex
+	tokens map[string]*model.Token
+}
+
+// 确保 MemoryStore 实现 TokenStore 接口
+var _ TokenStore = (*MemoryStore)(nil)
+
+// NewMemoryStore 创建内存令牌存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: make(map[string]*model.Token)}
+}
+
+// Load 读取令牌
+func (s *MemoryStore) Load(_ context.Context, openID string) (*model.Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	token, ok := s.tokens[openID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneToken(token), nil
+}
+
+// Save 保存令牌
+func (s *MemoryStore) Save(_ context.Context, openID string, token *model.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[openID] = cloneToken(token)
+	return nil
+}
+
+// Delete 删除令牌，令牌不存在时不返回错误
+func (s *MemoryStore) Delete(_ context.Context, openID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, openID)
+	return nil
+}
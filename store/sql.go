@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// Placeholder 生成 SQL 语句中第 n 个参数（从1开始）的占位符
+type Placeholder func(n int) string
+
+var (
+	// QuestionPlaceholder 使用 ? 作为占位符，适用于 SQLite、MySQL
+	QuestionPlaceholder Placeholder = func(int) string { return "?" }
+	// DollarPlaceholder 使用 $1、$2 作为占位符，适用于 PostgreSQL
+	DollarPlaceholder Placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+)
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStore 基于 database/sql 的令牌存储
+//
+// 令牌以 JSON 格式保存在 token 列中，表结构见 CreateTable。
+// SQLStore 不依赖具体数据库驱动，只使用各数据库通用的 SQL 语法。
+// 使用 MySQL 时需在 DSN 中设置 clientFoundRows=true，使 UPDATE 返回匹配行数。
+type SQLStore struct {
+	db          *sql.DB
+	table       string
+	placeholder Placeholder
+}
+
+// 确保 SQLStore 实现 TokenStore 接口
+var _ TokenStore = (*SQLStore)(nil)
+
+// NewSQLStore 创建 SQL 令牌存储
+//
+// table 为空时使用 tencent_doc_tokens；placeholder 为空时使用 QuestionPlaceholder。
+func NewSQLStore(db *sql.DB, table string, placeholder Placeholder) (*SQLStore, error) {
+	if table == "" {
+		table = "tencent_doc_tokens"
+	}
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name: %q", table)
+	}
+	if placeholder == nil {
+		placeholder = QuestionPlaceholder
+	}
+
+	return &SQLStore{db: db, table: table, placeholder: placeholder}, nil
+}
+
+// CreateTable 创建令牌表（已存在时跳过）
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	open_id VARCHAR(128) NOT NULL PRIMARY KEY,
+	token TEXT NOT NULL,
+	updated_at BIGINT NOT NULL
+)`, s.table)
+
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("create token table failed: %w", err)
+	}
+	return nil
+}
+
+// Load 读取令牌
+func (s *SQLStore) Load(ctx context.Context, openID string) (*model.Token, error) {
+	query := fmt.Sprintf("SELECT token FROM %s WHERE open_id = %s", s.table, s.placeholder(1))
+
+	var data string
+	err := s.db.QueryRowContext(ctx, query, openID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query token failed: %w", err)
+	}
+
+	var token model.Token
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return nil, fmt.Errorf("decode token failed: %w", err)
+	}
+	return &token, nil
+}
+
+// Save 保存令牌，已存在时覆盖
+func (s *SQLStore) Save(ctx context.Context, openID string, token *model.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("encode token failed: %w", err)
+	}
+	now := time.Now().Unix()
+
+	// 先更新再插入，避免依赖各数据库不同的 UPSERT 语法
+	updated, err := s.update(ctx, openID, data, now)
+	if err != nil || updated {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (open_id, token, updated_at) VALUES (%s, %s, %s)",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+	if _, insertErr := s.db.ExecContext(ctx, insert, openID, string(data), now); insertErr != nil {
+		// 其他进程可能在 UPDATE 和 INSERT 之间插入了同一用户的令牌，导致主键冲突，此时改为更新
+		if updated, err := s.update(ctx, openID, data, now); err == nil && updated {
+			return nil
+		}
+		return fmt.Errorf("insert token failed: %w", insertErr)
+	}
+	return nil
+}
+
+// update 更新已存在的令牌，返回是否匹配到记录
+func (s *SQLStore) update(ctx context.Context, openID string, data []byte, now int64) (bool, error) {
+	update := fmt.Sprintf("UPDATE %s SET token = %s, updated_at = %s WHERE open_id = %s",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+	res, err := s.db.ExecContext(ctx, update, string(data), now, openID)
+	if err != nil {
+		return false, fmt.Errorf("update token failed: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("update token failed: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// Delete 删除令牌，令牌不存在时不返回错误
+func (s *SQLStore) Delete(ctx context.Context, openID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE open_id = %s", s.table, s.placeholder(1))
+	if _, err := s.db.ExecContext(ctx, query, openID); err != nil {
+		return fmt.Errorf("delete token failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Package store 提供访问令牌的持久化存储。
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// ErrNotFound 指定用户的令牌不存在
+var ErrNotFound = errors.New("token not found")
+
+// TokenStore 令牌存储接口，以用户 OpenID 为键保存访问令牌
+//
+// 实现需要保证并发安全。Load 在令牌不存在时应返回 ErrNotFound。
+type TokenStore interface {
+	Load(ctx context.Context, openID string) (*model.Token, error)
+	Save(ctx context.Context, openID string, token *model.Token) error
+	Delete(ctx context.Context, openID string) error
+}
+
+func cloneToken(token *model.Token) *model.Token {
+	cp := *token
+	return &cp
+}
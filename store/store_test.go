@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	_ "modernc.org/sqlite"
+)
+
+func testTokenStore(t *testing.T, s TokenStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := s.Load(ctx, "u1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load() missing error = %v, want ErrNotFound", err)
+	}
+
+	token := &model.Token{AccessToken: "a1", RefreshToken: "r1", UserID: "u1", ExpiresAt: 1700000000}
+	if err := s.Save(ctx, "u1", token); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// 覆盖写入
+	token.AccessToken = "a2"
+	if err := s.Save(ctx, "u1", token); err != nil {
+		t.Fatalf("Save() overwrite error = %v", err)
+	}
+	if err := s.Save(ctx, "u2", &model.Token{AccessToken: "b1"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := s.Load(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if *got != *token {
+		t.Fatalf("Load() = %+v, want %+v", got, token)
+	}
+
+	if err := s.Delete(ctx, "u1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete(ctx, "u1"); err != nil {
+		t.Fatalf("Delete() missing error = %v", err)
+	}
+	if _, err := s.Load(ctx, "u1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load() after Delete error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Load(ctx, "u2"); err != nil {
+		t.Fatalf("Load() other user error = %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+	testTokenStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tokens", "tokens.json")
+	testTokenStore(t, NewFileStore(path))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("file mode = %v, want 0600", perm)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temp files left behind: %v", entries)
+	}
+}
+
+func TestSQLStore(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "tokens.db"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	s, err := NewSQLStore(db, "", nil)
+	if err != nil {
+		t.Fatalf("NewSQLStore() error = %v", err)
+	}
+	if err := s.CreateTable(context.Background()); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	testTokenStore(t, s)
+}
+
+func TestSQLStoreConcurrentFirstSave(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "tokens.db")+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	s, err := NewSQLStore(db, "", nil)
+	if err != nil {
+		t.Fatalf("NewSQLStore() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := s.CreateTable(ctx); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	// 多个进程同时保存同一用户的第一个令牌，都可能在 UPDATE 时匹配不到记录
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Save(ctx, "u1", &model.Token{AccessToken: fmt.Sprintf("a%d", i)}); err != nil {
+				t.Errorf("Save() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := s.Load(ctx, "u1"); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+}
+
+func TestNewSQLStoreRejectsInvalidTable(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSQLStore(nil, "tokens; DROP TABLE users", nil); err == nil {
+		t.Fatal("NewSQLStore() error = nil, want error")
+	}
+}
@@ -0,0 +1,55 @@
+package tdoctest
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) asyncExport(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	operationID, apiErr := s.tree.startExport(r.PathValue("id"), r.FormValue("exportType"))
+	s.mu.Unlock()
+
+	if apiErr != nil {
+		writeError(w, apiErr.Ret, apiErr.Msg)
+		return
+	}
+	writeData(w, map[string]string{"operationID": operationID})
+}
+
+func (s *Server) exportProgress(w http.ResponseWriter, r *http.Request, _ string) {
+	s.mu.Lock()
+	progress, url, apiErr := s.tree.pollExport(r.PathValue("id"), r.URL.Query().Get("operationID"))
+	s.mu.Unlock()
+
+	if apiErr != nil {
+		writeError(w, apiErr.Ret, apiErr.Msg)
+		return
+	}
+	data := map[string]interface{}{"progress": progress}
+	if url != "" {
+		data["url"] = url
+	}
+	writeData(w, data)
+}
+
+// ExportURL 返回导出任务的下载地址，下载不需要鉴权，与 COS 预签名地址一致
+func (s *Server) ExportURL(operationID string) string {
+	return s.tree.exportURL(operationID)
+}
+
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	e, ok := s.tree.exports[r.PathValue("operationID")]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": e.filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(e.content)))
+	w.Write(e.content)
+}
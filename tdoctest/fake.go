@@ -0,0 +1,344 @@
+package tdoctest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/chinahtl/tencent-doc-sdk/client"
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// FakeBaseURL FakeClient 返回的文件地址和导出下载地址的前缀，不可访问
+const FakeBaseURL = "https://tdoctest.invalid"
+
+var _ client.TencentDocClient = (*FakeClient)(nil)
+
+// Call FakeClient 记录的一次调用
+type Call struct {
+	Method string        // 方法名，如 "ListDocuments"
+	Args   []interface{} // 除 ctx 和 opts 外的参数，指针参数记录为调用时的值拷贝
+}
+
+// FakeClient 纯内存实现的 client.TencentDocClient，用于单元测试
+//
+// 与 Server 共用文件树和导出逻辑：导出任务的进度在每次 GetExportProgress 时推进，
+// 调用次数达到导出步数（默认3）后完成。所有调用按顺序记录，可通过 Calls 断言。
+// FakeClient 并发安全。
+type FakeClient struct {
+	mu     sync.Mutex
+	tree   *tree
+	user   model.UserInfo
+	errs   map[string][]error // 方法名 -> 待返回的错误
+	calls  []Call
+	tokens int
+}
+
+// NewFakeClient 创建 FakeClient，并按顺序添加 files
+func NewFakeClient(files ...File) *FakeClient {
+	f := &FakeClient{
+		tree: newTree(FakeBaseURL),
+		user: model.UserInfo{OpenID: DefaultOpenID, Nick: "测试用户"},
+		errs: make(map[string][]error),
+	}
+	for _, file := range files {
+		f.tree.add(file)
+	}
+	return f
+}
+
+// AddFile 添加文件或文件夹，ID 已存在时覆盖，返回文件ID
+func (f *FakeClient) AddFile(file File) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tree.add(file)
+}
+
+// RemoveFile 删除文件，删除文件夹时不会删除其中的文件
+func (f *FakeClient) RemoveFile(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tree.remove(id)
+}
+
+// File 返回文件的副本
+func (f *FakeClient) File(id string) (File, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tree.get(id)
+}
+
+// SetExportSteps 设置导出任务完成前需要查询进度的次数
+func (f *FakeClient) SetExportSteps(steps int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if steps > 0 {
+		f.tree.exportSteps = steps
+	}
+}
+
+// SetUser 设置 GetUserInfo 返回的用户，及签发令牌中的 UserID
+func (f *FakeClient) SetUser(user model.UserInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.user = user
+}
+
+// FailNext 使 method 的下一次调用返回 err，多次调用时按顺序依次返回
+//
+// err 会像真实客户端一样被包装，可通过 errors.Is/errors.As 判断。
+func (f *FakeClient) FailNext(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[method] = append(f.errs[method], err)
+}
+
+// Calls 按调用顺序返回所有调用记录
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// CallsTo 返回 method 的调用记录
+func (f *FakeClient) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, c := range f.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// ResetCalls 清空调用记录
+func (f *FakeClient) ResetCalls() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// ExportContent 返回导出任务的文件内容和文件名
+func (f *FakeClient) ExportContent(operationID string) ([]byte, string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, ok := f.tree.exports[operationID]
+	if !ok {
+		return nil, "", false
+	}
+	return slices.Clone(e.content), e.filename, true
+}
+
+// begin 记录调用并返回应当失败的错误，调用方需持有 f.mu
+func (f *FakeClient) begin(ctx context.Context, method string, args ...interface{}) error {
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if errs := f.errs[method]; len(errs) > 0 {
+		f.errs[method] = errs[1:]
+		return errs[0]
+	}
+	return nil
+}
+
+// GetAuthURL 实现 client.TencentDocClient
+func (f *FakeClient) GetAuthURL() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: "GetAuthURL"})
+	return FakeBaseURL + constant.AuthPath + "?client_id=" + DefaultClientID + "&response_type=code"
+}
+
+// ExchangeToken 实现 client.TencentDocClient，任意非空授权码均可换取令牌
+func (f *FakeClient) ExchangeToken(ctx context.Context, code string, _ ...client.CallOption) (*model.TokenResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.begin(ctx, "ExchangeToken", code)
+	if err == nil && code == "" {
+		err = retError(constant.RetInvalidParams, "invalid grant")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("exchange token failed: %w", err)
+	}
+	return f.issueToken(), nil
+}
+
+// RefreshToken 实现 client.TencentDocClient，任意非空刷新令牌均可刷新
+func (f *FakeClient) RefreshToken(ctx context.Context, refreshToken string, _ ...client.CallOption) (*model.TokenResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.begin(ctx, "RefreshToken", refreshToken)
+	if err == nil && refreshToken == "" {
+		err = retError(constant.RetInvalidParams, "invalid grant")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("refresh token failed: %w", err)
+	}
+	return f.issueToken(), nil
+}
+
+func (f *FakeClient) issueToken() *model.TokenResponse {
+	f.tokens++
+	return &model.TokenResponse{
+		Token: model.Token{
+			AccessToken:  fmt.Sprintf("fake-access-%d", f.tokens),
+			RefreshToken: fmt.Sprintf("fake-refresh-%d", f.tokens),
+			ExpiresIn:    7200,
+			TokenType:    "Bearer",
+			UserID:       f.user.OpenID,
+			Scope:        constant.AllScope,
+		},
+		Scope: constant.AllScope,
+	}
+}
+
+// GetUserInfo 实现 client.TencentDocClient
+func (f *FakeClient) GetUserInfo(ctx context.Context, _ ...client.CallOption) (*model.UserInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.begin(ctx, "GetUserInfo"); err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	user := f.user
+	return &user, nil
+}
+
+// ListDocuments 实现 client.TencentDocClient，默认参数与 client.Client 一致
+func (f *FakeClient) ListDocuments(
+	ctx context.Context,
+	params *model.ListParams,
+	_ ...client.CallOption,
+) (*model.ListDocumentsResponse, error) {
+	var p model.ListParams
+	if params != nil {
+		p = *params
+	}
+	if p.ListType == "" {
+		p.ListType = constant.ListTypeFolder
+	}
+	if p.SortType == "" {
+		p.SortType = constant.SortTypeBrowse
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.begin(ctx, "ListDocuments", p); err != nil {
+		return nil, fmt.Errorf("list documents failed: %w", err)
+	}
+	docs, next, apiErr := f.tree.list(p)
+	if apiErr != nil {
+		return nil, fmt.Errorf("list documents failed: %w", apiErr)
+	}
+
+	result := &model.ListDocumentsResponse{Msg: "Succeed"}
+	result.Data.Next = next
+	result.Data.List = docs
+	return result, nil
+}
+
+// SearchDocuments 实现 client.TencentDocClient
+func (f *FakeClient) SearchDocuments(
+	ctx context.Context,
+	params *model.SearchParams,
+	_ ...client.CallOption,
+) (*model.SearchDocumentsResponse, error) {
+	var p model.SearchParams
+	if params != nil {
+		p = *params
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.begin(ctx, "SearchDocuments", p); err != nil {
+		return nil, fmt.Errorf("search documents failed: %w", err)
+	}
+	found, apiErr := f.tree.search(p)
+	if apiErr != nil {
+		return nil, fmt.Errorf("search documents failed: %w", apiErr)
+	}
+
+	result := &model.SearchDocumentsResponse{Msg: "Succeed"}
+	result.Data.Next = found.Next
+	result.Data.Total = found.Total
+	result.Data.HasMore = found.HasMore
+	result.Data.List = found.List
+	return result, nil
+}
+
+// GetFileMetadata 实现 client.TencentDocClient
+func (f *FakeClient) GetFileMetadata(ctx context.Context, fileID string, _ ...client.CallOption) (*model.FileMetadataResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.begin(ctx, "GetFileMetadata", fileID); err != nil {
+		return nil, fmt.Errorf("get file metadata failed: %w", err)
+	}
+	result, apiErr := f.tree.metadata(fileID, f.user.OpenID)
+	if apiErr != nil {
+		return nil, fmt.Errorf("get file metadata failed: %w", apiErr)
+	}
+	return result, nil
+}
+
+// ExportDocument 实现 client.TencentDocClient
+func (f *FakeClient) ExportDocument(
+	ctx context.Context,
+	docID string,
+	req *model.ExportRequest,
+	_ ...client.CallOption,
+) (*model.ExportResponse, error) {
+	var r model.ExportRequest
+	if req != nil {
+		r = *req
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.begin(ctx, "ExportDocument", docID, r); err != nil {
+		return nil, fmt.Errorf("export document failed: %w", err)
+	}
+	if docID == "" {
+		return nil, fmt.Errorf("document ID cannot be empty")
+	}
+	operationID, apiErr := f.tree.startExport(docID, r.ExportType)
+	if apiErr != nil {
+		return nil, fmt.Errorf("export document failed: %w", apiErr)
+	}
+
+	result := &model.ExportResponse{Msg: "Succeed"}
+	result.Data.OperationID = operationID
+	return result, nil
+}
+
+// GetExportProgress 实现 client.TencentDocClient，每次调用推进一步导出进度
+func (f *FakeClient) GetExportProgress(
+	ctx context.Context,
+	docID string,
+	operationID string,
+	_ ...client.CallOption,
+) (*model.ExportProgressResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.begin(ctx, "GetExportProgress", docID, operationID); err != nil {
+		return nil, fmt.Errorf("get export progress failed: %w", err)
+	}
+	if docID == "" {
+		return nil, fmt.Errorf("document ID cannot be empty")
+	}
+	if operationID == "" {
+		return nil, fmt.Errorf("operation ID cannot be empty")
+	}
+	progress, url, apiErr := f.tree.pollExport(docID, operationID)
+	if apiErr != nil {
+		return nil, fmt.Errorf("get export progress failed: %w", apiErr)
+	}
+
+	result := &model.ExportProgressResponse{Msg: "Succeed"}
+	result.Data.Progress = progress
+	result.Data.URL = url
+	return result, nil
+}
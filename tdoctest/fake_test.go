@@ -0,0 +1,116 @@
+package tdoctest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/tdoctest"
+)
+
+func TestFakeClientDocuments(t *testing.T) {
+	t.Parallel()
+
+	fake := tdoctest.NewFakeClient(
+		tdoctest.File{ID: "folder1", Title: "项目", Type: "folder"},
+		tdoctest.File{ID: "doc1", ParentID: "folder1", Title: "周报", Type: "doc", CreateTime: 100},
+		tdoctest.File{ID: "doc2", ParentID: "folder1", Title: "月报", Type: "doc", CreateTime: 200},
+	)
+	ctx := context.Background()
+
+	list, err := fake.ListDocuments(ctx, &model.ListParams{FolderID: "folder1", Limit: 1})
+	if err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if len(list.Data.List) != 1 || list.Data.Next != 1 {
+		t.Fatalf("ListDocuments() = %d docs, next %d", len(list.Data.List), list.Data.Next)
+	}
+
+	found, err := fake.SearchDocuments(ctx, &model.SearchParams{SearchKey: "周报"})
+	if err != nil || found.Data.Total != 1 || found.Data.List[0].ID != "doc1" {
+		t.Fatalf("SearchDocuments() = %+v, %v", found, err)
+	}
+
+	meta, err := fake.GetFileMetadata(ctx, "doc2")
+	if err != nil || meta.Data.Title != "月报" {
+		t.Fatalf("GetFileMetadata() = %+v, %v", meta, err)
+	}
+	if _, err := fake.GetFileMetadata(ctx, "missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("GetFileMetadata(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFakeClientExportProgress(t *testing.T) {
+	t.Parallel()
+
+	fake := tdoctest.NewFakeClient(tdoctest.File{ID: "sheet1", Title: "预算", Type: "sheet", Content: []byte("a,b,c")})
+	fake.SetExportSteps(2)
+	ctx := context.Background()
+
+	exp, err := fake.ExportDocument(ctx, "sheet1", &model.ExportRequest{})
+	if err != nil {
+		t.Fatalf("ExportDocument() error = %v", err)
+	}
+	opID := exp.Data.OperationID
+
+	for i, want := range []int{50, 100} {
+		progress, err := fake.GetExportProgress(ctx, "sheet1", opID)
+		if err != nil {
+			t.Fatalf("GetExportProgress() #%d error = %v", i, err)
+		}
+		if progress.Data.Progress != want {
+			t.Fatalf("GetExportProgress() #%d progress = %d, want %d", i, progress.Data.Progress, want)
+		}
+		if (progress.Data.URL != "") != (want == 100) {
+			t.Fatalf("GetExportProgress() #%d url = %q", i, progress.Data.URL)
+		}
+	}
+
+	content, filename, ok := fake.ExportContent(opID)
+	if !ok || string(content) != "a,b,c" || filename != "预算.xlsx" {
+		t.Fatalf("ExportContent() = %q, %q, %v", content, filename, ok)
+	}
+}
+
+func TestFakeClientCallsAndFailures(t *testing.T) {
+	t.Parallel()
+
+	fake := tdoctest.NewFakeClient(tdoctest.File{ID: "doc1", Title: "周报", Type: "doc"})
+	ctx := context.Background()
+
+	fake.FailNext("GetFileMetadata", model.ErrRateLimited)
+	if _, err := fake.GetFileMetadata(ctx, "doc1"); !errors.Is(err, model.ErrRateLimited) {
+		t.Fatalf("GetFileMetadata() error = %v, want ErrRateLimited", err)
+	}
+	if _, err := fake.GetFileMetadata(ctx, "doc1"); err != nil {
+		t.Fatalf("GetFileMetadata() after failure error = %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := fake.GetUserInfo(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetUserInfo() error = %v, want context.Canceled", err)
+	}
+
+	token, err := fake.ExchangeToken(ctx, "code")
+	if err != nil || token.UserID != tdoctest.DefaultOpenID {
+		t.Fatalf("ExchangeToken() = %+v, %v", token, err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("len(Calls()) = %d, want 4", len(calls))
+	}
+	if calls[0].Method != "GetFileMetadata" || calls[0].Args[0] != "doc1" {
+		t.Fatalf("Calls()[0] = %+v", calls[0])
+	}
+	if got := len(fake.CallsTo("GetFileMetadata")); got != 2 {
+		t.Fatalf("len(CallsTo(GetFileMetadata)) = %d, want 2", got)
+	}
+
+	fake.ResetCalls()
+	if got := len(fake.Calls()); got != 0 {
+		t.Fatalf("len(Calls()) after reset = %d", got)
+	}
+}
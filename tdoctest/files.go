@@ -0,0 +1,96 @@
+package tdoctest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// AddFile 添加文件或文件夹，ID 已存在时覆盖，返回文件ID
+func (s *Server) AddFile(f File) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.add(f)
+}
+
+// RemoveFile 删除文件，删除文件夹时不会删除其中的文件
+func (s *Server) RemoveFile(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree.remove(id)
+}
+
+// File 返回文件的副本
+func (s *Server) File(id string) (File, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.get(id)
+}
+
+// Files 按添加顺序返回所有文件的副本
+func (s *Server) Files() []File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tree.all()
+}
+
+func (s *Server) filter(w http.ResponseWriter, r *http.Request, _ string) {
+	q := r.URL.Query()
+	params := model.ListParams{
+		ListType: q.Get("listType"),
+		SortType: q.Get("sortType"),
+		FolderID: q.Get("folderID"),
+		FileType: q.Get("fileType"),
+	}
+	params.Asc, _ = strconv.Atoi(q.Get("asc"))
+	params.Start, _ = strconv.Atoi(q.Get("start"))
+	params.Limit, _ = strconv.Atoi(q.Get("limit"))
+
+	s.mu.Lock()
+	docs, next, apiErr := s.tree.list(params)
+	s.mu.Unlock()
+
+	if apiErr != nil {
+		writeError(w, apiErr.Ret, apiErr.Msg)
+		return
+	}
+	writeData(w, map[string]interface{}{"next": next, "list": docs})
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request, _ string) {
+	q := r.URL.Query()
+	params := model.SearchParams{
+		SearchKey:  q.Get("searchKey"),
+		SearchType: q.Get("searchType"),
+		ResultType: q.Get("resultType"),
+		FolderID:   q.Get("folderID"),
+		SortType:   q.Get("sortType"),
+		FileTypes:  q.Get("fileTypes"),
+	}
+	params.Offset, _ = strconv.Atoi(q.Get("offset"))
+	params.Size, _ = strconv.Atoi(q.Get("size"))
+	params.Asc, _ = strconv.Atoi(q.Get("asc"))
+
+	s.mu.Lock()
+	result, apiErr := s.tree.search(params)
+	s.mu.Unlock()
+
+	if apiErr != nil {
+		writeError(w, apiErr.Ret, apiErr.Msg)
+		return
+	}
+	writeData(w, result)
+}
+
+func (s *Server) metadata(w http.ResponseWriter, r *http.Request, openID string) {
+	s.mu.Lock()
+	resp, apiErr := s.tree.metadata(r.PathValue("id"), openID)
+	s.mu.Unlock()
+
+	if apiErr != nil {
+		writeError(w, apiErr.Ret, apiErr.Msg)
+		return
+	}
+	writeData(w, resp.Data)
+}
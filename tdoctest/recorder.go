@@ -0,0 +1,373 @@
+package tdoctest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+// Redacted 磁带中敏感字段被替换后的值
+const Redacted = "[REDACTED]"
+
+// ErrUnmatchedRequest 回放时没有录制的交互与请求匹配
+var ErrUnmatchedRequest = errors.New("tdoctest: no recorded interaction matches request")
+
+// RecorderMode 录制回放模式
+type RecorderMode int
+
+const (
+	// ModeReplay 只从磁带回放，不访问网络，未匹配的请求返回 ErrUnmatchedRequest
+	ModeReplay RecorderMode = iota
+	// ModeRecord 转发真实请求并录制，调用 Save 后写入磁带文件
+	ModeRecord
+)
+
+// Cassette 磁带文件，按请求发生的顺序保存交互
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction 一次请求及其响应
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest 脱敏后的请求
+type RecordedRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // 请求体不是 UTF-8 文本时为 base64
+}
+
+// RecordedResponse 脱敏后的响应
+type RecordedResponse struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // 响应体不是 UTF-8 文本时为 base64
+}
+
+// Matcher 判断请求与录制的请求是否匹配，两者均已脱敏
+type Matcher func(req, recorded *RecordedRequest) bool
+
+// MatchMethodURL 方法和地址（含查询参数）相同即匹配，是默认的匹配规则
+func MatchMethodURL(req, recorded *RecordedRequest) bool {
+	return req.Method == recorded.Method && req.URL == recorded.URL
+}
+
+// MatchMethodURLBody 方法、地址和请求体均相同才匹配
+func MatchMethodURLBody(req, recorded *RecordedRequest) bool {
+	return MatchMethodURL(req, recorded) && req.Body == recorded.Body
+}
+
+// RecorderOption 录制回放配置选项
+type RecorderOption func(*Recorder)
+
+// WithRecordTransport 设置录制模式下实际发送请求的 Transport，默认为 http.DefaultTransport
+func WithRecordTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.base = transport
+	}
+}
+
+// WithMatcher 设置回放时的请求匹配规则，默认为 MatchMethodURL
+func WithMatcher(matcher Matcher) RecorderOption {
+	return func(r *Recorder) {
+		r.matcher = matcher
+	}
+}
+
+// WithScrubKeys 追加需要脱敏的请求头、查询参数、表单字段和 JSON 字段，不区分大小写
+//
+// Access-Token、access_token、client_secret、code、refresh_token 等字段始终脱敏。
+func WithScrubKeys(keys ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, key := range keys {
+			r.scrubKeys[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// Recorder 录制和回放 HTTP 交互的 http.RoundTripper，可通过 config.WithHttpTransport 使用
+//
+// 录制模式下转发请求并保存脱敏后的交互；回放模式下按录制顺序返回第一个尚未使用且匹配的交互，
+// 因此同一请求多次录制（如轮询导出进度）时会依次回放。请求在匹配前使用相同规则脱敏，
+// 令牌不同也能匹配。Recorder 并发安全。
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	base      http.RoundTripper
+	matcher   Matcher
+	scrubKeys map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder 创建 Recorder，回放模式下从 path 读取磁带，文件不存在时返回错误
+func NewRecorder(path string, mode RecorderMode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		base:      http.DefaultTransport,
+		matcher:   MatchMethodURL,
+		scrubKeys: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette failed: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("parse cassette %s failed: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Save 将录制的交互写入磁带文件，回放模式下不做任何操作
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode cassette failed: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("create cassette directory failed: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write cassette failed: %w", err)
+	}
+	return nil
+}
+
+// Unused 返回回放模式下尚未被请求使用的交互
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []*Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := r.scrubRequest(req, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matcher(recorded, &interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		body, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("decode recorded response failed: %w", err)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s (cassette %s)", ErrUnmatchedRequest, recorded.Method, recorded.URL, r.path)
+}
+
+func (r *Recorder) record(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := r.scrubHeader(resp.Header)
+	scrubbed := r.scrubBody(body, resp.Header.Get("Content-Type"))
+	interaction := &Interaction{
+		Request:  *recorded,
+		Response: RecordedResponse{StatusCode: resp.StatusCode, Header: header},
+	}
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(scrubbed)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// readRequestBody 读取请求体并恢复 req.Body，使请求仍可发送
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read request body failed: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (r *Recorder) isSensitive(key string) bool {
+	return util.IsSensitiveKey(key) || r.scrubKeys[strings.ToLower(key)]
+}
+
+func (r *Recorder) scrubRequest(req *http.Request, body []byte) *RecordedRequest {
+	u := *req.URL
+	if u.RawQuery != "" {
+		u.RawQuery = r.scrubValues(u.Query()).Encode()
+	}
+	recorded := &RecordedRequest{
+		Method: req.Method,
+		URL:    u.String(),
+		Header: r.scrubHeader(req.Header),
+	}
+	recorded.Body, recorded.BodyEncoding = encodeBody(r.scrubBody(body, req.Header.Get("Content-Type")))
+	return recorded
+}
+
+func (r *Recorder) scrubHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	scrubbed := header.Clone()
+	for key := range scrubbed {
+		if r.isSensitive(key) {
+			scrubbed[key] = []string{Redacted}
+		}
+	}
+	return scrubbed
+}
+
+func (r *Recorder) scrubValues(values url.Values) url.Values {
+	for key := range values {
+		if r.isSensitive(key) {
+			values[key] = []string{Redacted}
+		}
+	}
+	return values
+}
+
+// scrubBody 脱敏表单和 JSON 格式的请求体或响应体，其他格式原样返回
+func (r *Recorder) scrubBody(body []byte, contentType string) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		return []byte(r.scrubValues(values).Encode())
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var v interface{}
+		if decoder.Decode(&v) != nil || !r.scrubJSON(v) {
+			return body
+		}
+		scrubbed, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return scrubbed
+	}
+	return body
+}
+
+// scrubJSON 原地替换 JSON 中的敏感字段，返回是否有字段被替换
+func (r *Recorder) scrubJSON(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if r.isSensitive(key) {
+				v[key] = Redacted
+				changed = true
+			} else if r.scrubJSON(value) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if r.scrubJSON(value) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
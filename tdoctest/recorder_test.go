@@ -0,0 +1,84 @@
+package tdoctest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/client"
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/tdoctest"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+	ctx := context.Background()
+
+	rec, err := tdoctest.NewRecorder(path, tdoctest.ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder(record) error = %v", err)
+	}
+	c := client.NewClient(append(srv.ClientOptions(), config.WithHttpTransport(rec))...)
+	token, err := c.ExchangeToken(ctx, srv.AuthCode(tdoctest.DefaultOpenID))
+	if err != nil {
+		t.Fatalf("ExchangeToken() error = %v", err)
+	}
+	want, err := c.ListDocuments(ctx, &model.ListParams{FolderID: "folder1", Limit: 5})
+	if err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette: %v", err)
+	}
+	for _, secret := range []string{token.AccessToken, token.RefreshToken, tdoctest.DefaultClientSecret} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("cassette contains secret %q", secret)
+		}
+	}
+
+	srv.Close()
+	replay, err := tdoctest.NewRecorder(path, tdoctest.ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder(replay) error = %v", err)
+	}
+	c = client.NewClient(append(srv.ClientOptions(), config.WithHttpTransport(replay))...)
+	if _, err := c.ExchangeToken(ctx, "another-code"); err != nil {
+		t.Fatalf("replayed ExchangeToken() error = %v", err)
+	}
+	got, err := c.ListDocuments(ctx, &model.ListParams{FolderID: "folder1", Limit: 5})
+	if err != nil {
+		t.Fatalf("replayed ListDocuments() error = %v", err)
+	}
+	if len(got.Data.List) != len(want.Data.List) || got.Data.Next != want.Data.Next {
+		t.Fatalf("replayed ListDocuments() = %d docs, next %d; want %d, %d",
+			len(got.Data.List), got.Data.Next, len(want.Data.List), want.Data.Next)
+	}
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Fatalf("Unused() = %d interactions", len(unused))
+	}
+
+	if _, err := c.GetFileMetadata(ctx, "doc01"); !errors.Is(err, tdoctest.ErrUnmatchedRequest) {
+		t.Fatalf("unmatched GetFileMetadata() error = %v, want ErrUnmatchedRequest", err)
+	}
+}
+
+func TestRecorderReplayMissingCassette(t *testing.T) {
+	t.Parallel()
+
+	_, err := tdoctest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), tdoctest.ModeReplay)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("NewRecorder() error = %v, want os.ErrNotExist", err)
+	}
+}
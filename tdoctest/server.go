@@ -0,0 +1,444 @@
+// Package tdoctest 提供模拟腾讯文档 OpenAPI 的测试服务器，便于在离线环境下对基于 SDK 的代码做集成测试。
+//
+// Server 基于 httptest.Server，模拟了 OAuth 授权、文档列表（/drive/v2/filter）、搜索、文件元数据、
+// 异步导出和导出进度接口，使用内存中的文件树，并支持配置延迟和注入故障：
+//
+//	srv := tdoctest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddFile(tdoctest.File{ID: "folder1", Title: "项目", Type: "folder"})
+//	srv.AddFile(tdoctest.File{ID: "doc1", ParentID: "folder1", Title: "周报", Type: "doc"})
+//
+//	docClient := client.NewClient(srv.ClientOptions()...).WithToken(srv.IssueToken(tdoctest.DefaultOpenID))
+package tdoctest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// 默认的应用和用户信息
+const (
+	DefaultClientID     = "tdoctest-client"
+	DefaultClientSecret = "tdoctest-secret"
+	DefaultRedirectURI  = "http://localhost/callback"
+	DefaultOpenID       = "tdoctest-user"
+)
+
+// Endpoint 模拟的接口，用于注入故障和统计请求次数
+type Endpoint string
+
+// 模拟的接口列表
+const (
+	EndpointAuthorize      Endpoint = "authorize"
+	EndpointToken          Endpoint = "token"
+	EndpointUserInfo       Endpoint = "userinfo"
+	EndpointFilter         Endpoint = "filter"
+	EndpointSearch         Endpoint = "search"
+	EndpointMetadata       Endpoint = "metadata"
+	EndpointAsyncExport    Endpoint = "async-export"
+	EndpointExportProgress Endpoint = "export-progress"
+	EndpointDownload       Endpoint = "download"
+)
+
+// Fault 注入的故障
+type Fault struct {
+	Endpoint   Endpoint      // 匹配的接口，为空时匹配所有接口
+	Ret        int           // 返回的 ret 码，HTTP 状态码为200
+	Msg        string        // 返回的错误信息，默认为 "injected fault"
+	StatusCode int           // 返回的 HTTP 状态码，设置后优先于 Ret
+	Delay      time.Duration // 响应前等待的时间，超过客户端超时即可模拟超时
+	Times      int           // 生效次数，为0时一直生效
+}
+
+// Option 服务器配置选项
+type Option func(*Server)
+
+// WithClient 设置应用的 ClientID 和 ClientSecret
+func WithClient(clientID, clientSecret string) Option {
+	return func(s *Server) {
+		s.clientID = clientID
+		s.clientSecret = clientSecret
+	}
+}
+
+// WithLatency 设置每个请求的响应延迟
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// WithTokenTTL 设置签发的访问令牌有效期，默认为2小时
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.tokenTTL = ttl
+	}
+}
+
+// WithExportSteps 设置导出任务完成前需要查询进度的次数，默认为3，第 n 次查询时进度为 n*100/steps
+func WithExportSteps(steps int) Option {
+	return func(s *Server) {
+		if steps > 0 {
+			s.tree.exportSteps = steps
+		}
+	}
+}
+
+// Server 模拟腾讯文档 OpenAPI 的测试服务器，并发安全
+type Server struct {
+	// URL 服务器地址，可传给 config.WithBaseURL
+	URL string
+
+	srv          *httptest.Server
+	clientID     string
+	clientSecret string
+	tokenTTL     time.Duration
+
+	mu      sync.Mutex
+	latency time.Duration
+	faults  []*Fault
+	counts  map[Endpoint]int
+	users   map[string]model.UserInfo
+	codes   map[string]string // 授权码 -> openID
+	tokens  map[string]*grant // 访问令牌 -> 授权
+	refresh map[string]string // 刷新令牌 -> openID
+	tree    *tree
+}
+
+type grant struct {
+	openID    string
+	expiresAt time.Time
+}
+
+// NewServer 创建并启动测试服务器，使用完毕后需要调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		clientID:     DefaultClientID,
+		clientSecret: DefaultClientSecret,
+		tokenTTL:     2 * time.Hour,
+		counts:       make(map[Endpoint]int),
+		users:        make(map[string]model.UserInfo),
+		codes:        make(map[string]string),
+		tokens:       make(map[string]*grant),
+		refresh:      make(map[string]string),
+		tree:         newTree(""),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.users[DefaultOpenID] = model.UserInfo{OpenID: DefaultOpenID, Nick: "测试用户", Source: "tdoctest"}
+
+	mux := http.NewServeMux()
+	s.handle(mux, "GET "+constant.AuthPath, EndpointAuthorize, s.authorize)
+	s.handle(mux, "POST "+constant.TokenPath, EndpointToken, s.token)
+	s.handle(mux, "GET "+constant.UserInfoPath, EndpointUserInfo, s.userInfo)
+	s.handle(mux, "GET "+constant.APIPath+"/drive/v2/filter", EndpointFilter, s.authed(s.filter))
+	s.handle(mux, "GET "+constant.APIPath+"/drive/v2/search", EndpointSearch, s.authed(s.search))
+	s.handle(mux, "GET "+constant.APIPath+"/drive/v2/files/{id}/metadata", EndpointMetadata, s.authed(s.metadata))
+	s.handle(mux, "POST "+constant.APIPath+"/drive/v2/files/{id}/async-export", EndpointAsyncExport, s.authed(s.asyncExport))
+	s.handle(mux, "GET "+constant.APIPath+"/drive/v2/files/{id}/export-progress", EndpointExportProgress, s.authed(s.exportProgress))
+	s.handle(mux, "GET /tdoctest/download/{operationID}", EndpointDownload, s.download)
+
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+	s.tree.baseURL = s.URL
+	return s
+}
+
+// Close 关闭服务器
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client 返回访问服务器的 HTTP 客户端
+func (s *Server) Client() *http.Client {
+	return s.srv.Client()
+}
+
+// ClientOptions 返回将 SDK 指向该服务器所需的配置选项
+func (s *Server) ClientOptions() []config.Option {
+	return []config.Option{
+		config.WithBaseURL(s.URL),
+		config.WithClientID(s.clientID),
+		config.WithClientSecret(s.clientSecret),
+		config.WithRedirectURI(DefaultRedirectURI),
+	}
+}
+
+// SetLatency 修改每个请求的响应延迟
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// InjectFault 注入故障，多个故障同时匹配时先注入的优先
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults 清除所有故障
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// RequestCount 返回 endpoint 收到的请求次数，包含被注入故障的请求
+func (s *Server) RequestCount(endpoint Endpoint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[endpoint]
+}
+
+// AddUser 添加用户，OpenID 已存在时覆盖
+func (s *Server) AddUser(user model.UserInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.OpenID] = user
+}
+
+// AuthCode 为 openID 对应用户签发一次性授权码，可用于 ExchangeToken
+func (s *Server) AuthCode(openID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	code := "code-" + randomID()
+	s.codes[code] = openID
+	return code
+}
+
+// IssueToken 直接为 openID 对应用户签发令牌，跳过授权流程
+func (s *Server) IssueToken(openID string) *model.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issueToken(openID)
+}
+
+// ExpireToken 使访问令牌立即过期，之后使用该令牌的请求返回 ret 400007，可用于测试自动刷新
+func (s *Server) ExpireToken(accessToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.tokens[accessToken]; ok {
+		g.expiresAt = time.Now().Add(-time.Second)
+	}
+}
+
+func (s *Server) issueToken(openID string) *model.Token {
+	access := "access-" + randomID()
+	refresh := "refresh-" + randomID()
+	expiresAt := time.Now().Add(s.tokenTTL)
+	s.tokens[access] = &grant{openID: openID, expiresAt: expiresAt}
+	s.refresh[refresh] = openID
+
+	return &model.Token{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.tokenTTL / time.Second),
+		TokenType:    "Bearer",
+		UserID:       openID,
+		Scope:        constant.AllScope,
+	}
+}
+
+// handle 注册路由，统一处理请求计数、延迟和故障注入
+func (s *Server) handle(mux *http.ServeMux, pattern string, endpoint Endpoint, h http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.counts[endpoint]++
+		latency := s.latency
+		fault := s.matchFault(endpoint)
+		s.mu.Unlock()
+
+		if fault != nil {
+			latency += fault.Delay
+		}
+		if !sleep(r.Context(), latency) {
+			return
+		}
+
+		switch {
+		case fault == nil:
+			h(w, r)
+		case fault.StatusCode != 0:
+			writeJSON(w, fault.StatusCode, envelope{Ret: fault.Ret, Msg: fault.Msg})
+		case fault.Ret != 0:
+			writeError(w, fault.Ret, fault.Msg)
+		default:
+			// 仅有延迟的故障
+			h(w, r)
+		}
+	})
+}
+
+// matchFault 返回匹配的故障并扣减剩余次数，调用方需持有锁
+func (s *Server) matchFault(endpoint Endpoint) *Fault {
+	for i, f := range s.faults {
+		if f.Endpoint != "" && f.Endpoint != endpoint {
+			continue
+		}
+		matched := *f
+		if matched.Msg == "" {
+			matched.Msg = "injected fault"
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// authed 校验 OpenAPI 请求头中的 Access-Token、Client-Id 和 Open-Id
+func (s *Server) authed(h func(w http.ResponseWriter, r *http.Request, openID string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		openID, ret := s.checkToken(r.Header.Get("Access-Token"))
+		switch {
+		case ret != 0:
+			writeError(w, ret, "access token is invalid or expired")
+		case r.Header.Get("Client-Id") != s.clientID:
+			writeError(w, constant.RetInvalidParams, "client id mismatch")
+		case r.Header.Get("Open-Id") != "" && r.Header.Get("Open-Id") != openID:
+			writeError(w, constant.RetAccessTokenInvalid, "open id mismatch")
+		default:
+			h(w, r, openID)
+		}
+	}
+}
+
+func (s *Server) checkToken(accessToken string) (string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.tokens[accessToken]
+	if !ok {
+		return "", constant.RetAccessTokenInvalid
+	}
+	if time.Now().After(g.expiresAt) {
+		return "", constant.RetAccessTokenExpired
+	}
+	return g.openID, 0
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != s.clientID {
+		writeJSON(w, http.StatusBadRequest, envelope{Ret: constant.RetInvalidParams, Msg: "invalid client_id"})
+		return
+	}
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.String() == "" {
+		writeJSON(w, http.StatusBadRequest, envelope{Ret: constant.RetInvalidParams, Msg: "invalid redirect_uri"})
+		return
+	}
+
+	params := redirect.Query()
+	params.Set("code", s.AuthCode(DefaultOpenID))
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("client_id") != s.clientID || r.FormValue("client_secret") != s.clientSecret {
+		writeJSON(w, http.StatusUnauthorized, envelope{Ret: constant.RetInvalidParams, Msg: "invalid client credentials"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var openID string
+	var ok bool
+	switch r.FormValue("grant_type") {
+	case "authorization_code":
+		code := r.FormValue("code")
+		if openID, ok = s.codes[code]; ok {
+			delete(s.codes, code)
+		}
+	case "refresh_token":
+		rt := r.FormValue("refresh_token")
+		if openID, ok = s.refresh[rt]; ok {
+			delete(s.refresh, rt)
+		}
+	}
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, envelope{Ret: constant.RetInvalidParams, Msg: "invalid grant"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.issueToken(openID))
+}
+
+func (s *Server) userInfo(w http.ResponseWriter, r *http.Request) {
+	openID, ret := s.checkToken(r.URL.Query().Get("access_token"))
+	if ret != 0 {
+		writeError(w, ret, "access token is invalid or expired")
+		return
+	}
+
+	s.mu.Lock()
+	user, ok := s.users[openID]
+	s.mu.Unlock()
+	if !ok {
+		user = model.UserInfo{OpenID: openID}
+	}
+	writeData(w, user)
+}
+
+type envelope struct {
+	Ret  int         `json:"ret"`
+	Msg  string      `json:"msg"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", "tdoctest-"+randomID())
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, envelope{Msg: "Succeed", Data: data})
+}
+
+func writeError(w http.ResponseWriter, ret int, msg string) {
+	writeJSON(w, http.StatusOK, envelope{Ret: ret, Msg: msg})
+}
+
+// sleep 等待 d，请求被取消时返回 false
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
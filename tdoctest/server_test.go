@@ -0,0 +1,193 @@
+package tdoctest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/client"
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/tdoctest"
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+func newTestServer(t *testing.T, opts ...tdoctest.Option) *tdoctest.Server {
+	t.Helper()
+
+	srv := tdoctest.NewServer(opts...)
+	t.Cleanup(srv.Close)
+
+	srv.AddFile(tdoctest.File{ID: "folder1", Title: "项目", Type: "folder", CreateTime: 100})
+	for i := 1; i <= 25; i++ {
+		srv.AddFile(tdoctest.File{
+			ID:         fmt.Sprintf("doc%02d", i),
+			ParentID:   "folder1",
+			Title:      fmt.Sprintf("周报 %02d", i),
+			Type:       "doc",
+			CreateTime: int64(1000 + i),
+		})
+	}
+	srv.AddFile(tdoctest.File{ID: "sheet1", Title: "预算", Type: "sheet", CreateTime: 200, Content: []byte("a,b,c")})
+	return srv
+}
+
+func TestServerOAuthFlow(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t)
+	c := client.NewClient(srv.ClientOptions()...)
+	ctx := context.Background()
+
+	if _, err := c.ExchangeToken(ctx, "bogus"); err == nil {
+		t.Fatal("ExchangeToken() with invalid code error = nil")
+	}
+	token, err := c.ExchangeToken(ctx, srv.AuthCode(tdoctest.DefaultOpenID))
+	if err != nil {
+		t.Fatalf("ExchangeToken() error = %v", err)
+	}
+	if token.UserID != tdoctest.DefaultOpenID {
+		t.Fatalf("UserID = %q", token.UserID)
+	}
+
+	user, err := c.GetUserInfo(ctx)
+	if err != nil || user.OpenID != tdoctest.DefaultOpenID {
+		t.Fatalf("GetUserInfo() = %+v, %v", user, err)
+	}
+
+	// 访问令牌过期后自动刷新
+	srv.ExpireToken(token.AccessToken)
+	if _, err := c.GetFileMetadata(ctx, "sheet1"); err != nil {
+		t.Fatalf("GetFileMetadata() after expiry error = %v", err)
+	}
+	if got := srv.RequestCount(tdoctest.EndpointToken); got != 3 {
+		t.Fatalf("token requests = %d, want 3", got)
+	}
+	if c.Token().AccessToken == token.AccessToken {
+		t.Fatal("token was not refreshed")
+	}
+}
+
+func TestServerListAndSearch(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t)
+	c := client.NewClient(srv.ClientOptions()...).WithToken(srv.IssueToken(tdoctest.DefaultOpenID))
+	ctx := context.Background()
+
+	root, err := c.ListDocuments(ctx, &model.ListParams{})
+	if err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if len(root.Data.List) != 2 || root.Data.Next != 0 {
+		t.Fatalf("root listing = %d items, next %d", len(root.Data.List), root.Data.Next)
+	}
+
+	var ids []string
+	params := &model.ListParams{FolderID: "folder1", SortType: "time", Asc: 1}
+	for {
+		page, err := c.ListDocuments(ctx, params)
+		if err != nil {
+			t.Fatalf("ListDocuments() error = %v", err)
+		}
+		for _, doc := range page.Data.List {
+			ids = append(ids, doc.ID)
+		}
+		if page.Data.Next == 0 {
+			break
+		}
+		params.Start = page.Data.Next
+	}
+	if len(ids) != 25 || ids[0] != "doc01" || ids[24] != "doc25" {
+		t.Fatalf("paged listing = %v", ids)
+	}
+
+	found, err := c.SearchDocuments(ctx, &model.SearchParams{SearchKey: "周报", SearchType: "title", Size: 10})
+	if err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	if found.Data.Total != 25 || !found.Data.HasMore || len(found.Data.List) != 10 || found.Data.Next != 10 {
+		t.Fatalf("search = total %d, hasMore %v, %d items, next %d",
+			found.Data.Total, found.Data.HasMore, len(found.Data.List), found.Data.Next)
+	}
+
+	if _, err := c.GetFileMetadata(ctx, "missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("GetFileMetadata() error = %v, want model.ErrNotFound", err)
+	}
+}
+
+func TestServerExportAndDownload(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, tdoctest.WithExportSteps(2))
+	c := client.NewClient(srv.ClientOptions()...).WithToken(srv.IssueToken(tdoctest.DefaultOpenID))
+	ctx := context.Background()
+
+	exported, err := c.ExportDocument(ctx, "sheet1", &model.ExportRequest{})
+	if err != nil {
+		t.Fatalf("ExportDocument() error = %v", err)
+	}
+
+	var progress *model.ExportProgressResponse
+	for _, want := range []int{50, 100} {
+		progress, err = c.GetExportProgress(ctx, "sheet1", exported.Data.OperationID)
+		if err != nil {
+			t.Fatalf("GetExportProgress() error = %v", err)
+		}
+		if progress.Data.Progress != want {
+			t.Fatalf("progress = %d, want %d", progress.Data.Progress, want)
+		}
+	}
+
+	path, err := c.DownloadFromCOS(ctx, progress.Data.URL, t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadFromCOS() error = %v", err)
+	}
+	if filepath.Base(path) != "预算.xlsx" {
+		t.Fatalf("downloaded file = %q", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "a,b,c" {
+		t.Fatalf("downloaded content = %q", data)
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t)
+	policy := util.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	opts := append(srv.ClientOptions(), config.WithRetryPolicy(policy))
+	c := client.NewClient(opts...).WithToken(srv.IssueToken(tdoctest.DefaultOpenID))
+	ctx := context.Background()
+
+	// 5xx 和限流 ret 在重试后恢复
+	srv.InjectFault(tdoctest.Fault{Endpoint: tdoctest.EndpointMetadata, StatusCode: 503, Times: 1})
+	srv.InjectFault(tdoctest.Fault{Endpoint: tdoctest.EndpointMetadata, Ret: 400009, Times: 1})
+	if _, err := c.GetFileMetadata(ctx, "sheet1"); err != nil {
+		t.Fatalf("GetFileMetadata() error = %v", err)
+	}
+	if got := srv.RequestCount(tdoctest.EndpointMetadata); got != 3 {
+		t.Fatalf("metadata requests = %d, want 3", got)
+	}
+
+	srv.InjectFault(tdoctest.Fault{Endpoint: tdoctest.EndpointFilter, Ret: 400003})
+	if _, err := c.ListDocuments(ctx, &model.ListParams{}); !errors.Is(err, model.ErrPermissionDenied) {
+		t.Fatalf("ListDocuments() error = %v, want model.ErrPermissionDenied", err)
+	}
+
+	srv.InjectFault(tdoctest.Fault{Endpoint: tdoctest.EndpointSearch, Delay: time.Second})
+	_, err := c.SearchDocuments(ctx, &model.SearchParams{SearchKey: "周报"}, client.WithCallTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SearchDocuments() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	srv.ClearFaults()
+	if _, err := c.ListDocuments(ctx, &model.ListParams{}); err != nil {
+		t.Fatalf("ListDocuments() after ClearFaults error = %v", err)
+	}
+}
@@ -0,0 +1,393 @@
+package tdoctest
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+// RootFolderID 根目录ID
+const RootFolderID = "/"
+
+// File 内存文件树中的文件或文件夹
+type File struct {
+	ID             string // 文件ID，为空时自动生成
+	ParentID       string // 所在文件夹ID，为空时位于根目录
+	Title          string
+	Type           string // folder/doc/sheet/slide 等
+	OwnerName      string // 所有者名称，默认为 "测试用户"
+	CreateTime     int64  // 创建时间戳，为0时使用添加时间
+	LastModifyTime int64  // 最后修改时间戳，为0时等于 CreateTime
+	LastBrowseTime int64  // 最后浏览时间戳，为0时等于 LastModifyTime
+	Content        []byte // 导出的文件内容，为空时自动生成
+}
+
+// tree Server 和 FakeClient 共用的内存文件树及导出任务，不加锁，由调用方保证并发安全
+type tree struct {
+	baseURL     string // 文件访问地址和导出下载地址的前缀
+	exportSteps int
+	files       map[string]*File
+	order       []string // 文件添加顺序
+	exports     map[string]*export
+	sequence    int
+}
+
+type export struct {
+	fileID     string
+	exportType string
+	polls      int
+	content    []byte
+	filename   string
+}
+
+// defaultExportTypes 各文件类型的默认导出格式
+var defaultExportTypes = map[string]string{
+	constant.FileTypeDoc:   constant.ExportTypeDocx,
+	constant.FileTypeSheet: constant.ExportTypeXlsx,
+	constant.FileTypeSlide: constant.ExportTypePptx,
+}
+
+func newTree(baseURL string) *tree {
+	return &tree{
+		baseURL:     baseURL,
+		exportSteps: 3,
+		files:       make(map[string]*File),
+		exports:     make(map[string]*export),
+	}
+}
+
+func (t *tree) add(f File) string {
+	if f.ID == "" {
+		t.sequence++
+		f.ID = "file-" + strconv.Itoa(t.sequence)
+	}
+	if f.ParentID == "" {
+		f.ParentID = RootFolderID
+	}
+	if f.OwnerName == "" {
+		f.OwnerName = "测试用户"
+	}
+	if f.CreateTime == 0 {
+		f.CreateTime = time.Now().Unix()
+	}
+	if f.LastModifyTime == 0 {
+		f.LastModifyTime = f.CreateTime
+	}
+	if f.LastBrowseTime == 0 {
+		f.LastBrowseTime = f.LastModifyTime
+	}
+
+	if _, ok := t.files[f.ID]; !ok {
+		t.order = append(t.order, f.ID)
+	}
+	t.files[f.ID] = &f
+	return f.ID
+}
+
+func (t *tree) remove(id string) {
+	delete(t.files, id)
+	t.order = slices.DeleteFunc(t.order, func(v string) bool { return v == id })
+}
+
+func (t *tree) get(id string) (File, bool) {
+	f, ok := t.files[id]
+	if !ok {
+		return File{}, false
+	}
+	return *f, true
+}
+
+func (t *tree) all() []File {
+	files := make([]File, 0, len(t.order))
+	for _, id := range t.order {
+		files = append(files, *t.files[id])
+	}
+	return files
+}
+
+func (t *tree) fileURL(f *File) string {
+	return t.baseURL + "/doc/" + f.ID
+}
+
+func (t *tree) document(f *File) *model.Document {
+	return &model.Document{
+		ID:             f.ID,
+		Title:          f.Title,
+		Type:           f.Type,
+		URL:            t.fileURL(f),
+		Status:         "normal",
+		FileSource:     "personal",
+		IsCreator:      true,
+		CreatorName:    f.OwnerName,
+		IsOwner:        true,
+		OwnerName:      f.OwnerName,
+		CreateTime:     f.CreateTime,
+		LastModifyTime: f.LastModifyTime,
+		LastBrowseTime: f.LastBrowseTime,
+	}
+}
+
+// inFolder 判断文件是否位于 folderID 之下（含子文件夹）
+func (t *tree) inFolder(f *File, folderID string) bool {
+	if folderID == "" || folderID == RootFolderID {
+		return true
+	}
+	seen := make(map[string]bool)
+	for parent := f.ParentID; parent != RootFolderID && !seen[parent]; {
+		if parent == folderID {
+			return true
+		}
+		seen[parent] = true
+		p, ok := t.files[parent]
+		if !ok {
+			return false
+		}
+		parent = p.ParentID
+	}
+	return false
+}
+
+// list 按 /drive/v2/filter 的语义列出文件，出错时返回 ret 码
+func (t *tree) list(params model.ListParams) ([]*model.Document, int, *model.APIError) {
+	folderID := cmp.Or(params.FolderID, RootFolderID)
+	listType := cmp.Or(params.ListType, constant.ListTypeFolder)
+	limit := params.Limit
+	if limit <= 0 || limit > 20 {
+		limit = 20
+	}
+	if params.Start < 0 {
+		return nil, 0, retError(constant.RetInvalidParams, "invalid start")
+	}
+	if folderID != RootFolderID {
+		if folder, ok := t.files[folderID]; !ok || folder.Type != "folder" {
+			return nil, 0, retError(constant.RetNotFound, "folder not found")
+		}
+	}
+
+	var matched []*File
+	for _, id := range t.order {
+		f := t.files[id]
+		switch listType {
+		case constant.ListTypeFolder:
+			if f.ParentID != folderID {
+				continue
+			}
+		case constant.ListTypeFile:
+			if f.Type == "folder" || !t.inFolder(f, folderID) {
+				continue
+			}
+		default:
+			if !t.inFolder(f, folderID) {
+				continue
+			}
+		}
+		if matchFileType(f, params.FileType) {
+			matched = append(matched, f)
+		}
+	}
+
+	sortFiles(matched, params.SortType, params.Asc == 1)
+
+	page, next := paginate(matched, params.Start, limit)
+	docs := make([]*model.Document, 0, len(page))
+	for _, f := range page {
+		docs = append(docs, t.document(f))
+	}
+	return docs, next, nil
+}
+
+// searchResult /drive/v2/search 的 data 字段
+type searchResult struct {
+	Next    int                     `json:"next"`
+	Total   int                     `json:"total"`
+	HasMore bool                    `json:"hasMore"`
+	List    []*model.SearchDocument `json:"list"`
+}
+
+// search 按 /drive/v2/search 的语义搜索文件
+func (t *tree) search(params model.SearchParams) (*searchResult, *model.APIError) {
+	if params.SearchKey == "" {
+		return nil, retError(constant.RetInvalidParams, "searchKey is required")
+	}
+	size := params.Size
+	if size <= 0 {
+		size = 20
+	}
+	size = min(size, 50)
+
+	var matched []*File
+	for _, id := range t.order {
+		f := t.files[id]
+		field := f.Title
+		if params.SearchType == "owner" {
+			field = f.OwnerName
+		}
+		if !strings.Contains(strings.ToLower(field), strings.ToLower(params.SearchKey)) {
+			continue
+		}
+		if !t.inFolder(f, params.FolderID) || !matchFileType(f, params.FileTypes) {
+			continue
+		}
+		if params.ResultType == "folder" && f.Type != "folder" {
+			continue
+		}
+		matched = append(matched, f)
+	}
+
+	sortType := map[string]string{"create": "create", "browse": constant.SortTypeBrowse}[params.SortType]
+	sortFiles(matched, cmp.Or(sortType, constant.SortTypeTime), params.Asc == 1)
+
+	page, next := paginate(matched, params.Offset, size)
+	result := &searchResult{
+		Next:    next,
+		Total:   len(matched),
+		HasMore: next != 0,
+		List:    make([]*model.SearchDocument, 0, len(page)),
+	}
+	for _, f := range page {
+		result.List = append(result.List, &model.SearchDocument{
+			ID:             f.ID,
+			Title:          f.Title,
+			Type:           f.Type,
+			URL:            t.fileURL(f),
+			Status:         "normal",
+			OwnerName:      f.OwnerName,
+			FileSource:     "personal",
+			Highlight:      f.Title,
+			LastModifyTime: f.LastModifyTime,
+			LastModifyName: f.OwnerName,
+			CreateTime:     f.CreateTime,
+		})
+	}
+	return result, nil
+}
+
+// metadata 返回 /drive/v2/files/{id}/metadata 的响应
+func (t *tree) metadata(fileID, openID string) (*model.FileMetadataResponse, *model.APIError) {
+	f, ok := t.files[fileID]
+	if !ok {
+		return nil, retError(constant.RetNotFound, "file not found")
+	}
+
+	resp := &model.FileMetadataResponse{Msg: "Succeed"}
+	doc := t.document(f)
+	resp.Data.ID = doc.ID
+	resp.Data.Title = doc.Title
+	resp.Data.Type = doc.Type
+	resp.Data.URL = doc.URL
+	resp.Data.Status = doc.Status
+	resp.Data.IsCreator = doc.IsCreator
+	resp.Data.CreateTime = doc.CreateTime
+	resp.Data.CreatorName = doc.CreatorName
+	resp.Data.IsOwner = doc.IsOwner
+	resp.Data.OwnerName = doc.OwnerName
+	resp.Data.LastModifyTime = doc.LastModifyTime
+	resp.Data.LastModifyName = doc.OwnerName
+	resp.Data.OwnerID = openID
+	return resp, nil
+}
+
+// startExport 创建导出任务，返回任务ID
+func (t *tree) startExport(fileID, exportType string) (string, *model.APIError) {
+	f, ok := t.files[fileID]
+	if !ok {
+		return "", retError(constant.RetNotFound, "file not found")
+	}
+	if f.Type == "folder" {
+		return "", retError(constant.RetInvalidParams, "folder cannot be exported")
+	}
+
+	exportType = cmp.Or(exportType, defaultExportTypes[f.Type], constant.ExportTypePDF)
+	content := f.Content
+	if len(content) == 0 {
+		content = []byte(fmt.Sprintf("%s exported as %s", f.Title, exportType))
+	}
+
+	t.sequence++
+	operationID := "op-" + strconv.Itoa(t.sequence)
+	t.exports[operationID] = &export{
+		fileID:     f.ID,
+		exportType: exportType,
+		content:    slices.Clone(content),
+		filename:   f.Title + "." + exportType,
+	}
+	return operationID, nil
+}
+
+// pollExport 推进导出进度，返回当前进度，完成时返回下载地址
+func (t *tree) pollExport(fileID, operationID string) (int, string, *model.APIError) {
+	e, ok := t.exports[operationID]
+	if !ok || e.fileID != fileID {
+		return 0, "", retError(constant.RetNotFound, "export operation not found")
+	}
+
+	e.polls++
+	progress := min(100, e.polls*100/t.exportSteps)
+	if progress < 100 {
+		return progress, "", nil
+	}
+	return progress, t.exportURL(operationID), nil
+}
+
+func (t *tree) exportURL(operationID string) string {
+	return t.baseURL + "/tdoctest/download/" + operationID
+}
+
+func retError(ret int, msg string) *model.APIError {
+	return &model.APIError{StatusCode: 200, Ret: ret, Msg: msg}
+}
+
+func matchFileType(f *File, fileTypes string) bool {
+	if fileTypes == "" {
+		return true
+	}
+	return slices.Contains(strings.Split(fileTypes, "-"), f.Type)
+}
+
+// sortFiles 按 sortType 排序，默认倒序，相同时按ID排序保证结果稳定
+func sortFiles(files []*File, sortType string, asc bool) {
+	key := func(f *File) int64 {
+		switch sortType {
+		case constant.SortTypeTime:
+			return f.LastModifyTime
+		case "create":
+			return f.CreateTime
+		default:
+			return f.LastBrowseTime
+		}
+	}
+
+	slices.SortStableFunc(files, func(a, b *File) int {
+		var c int
+		if sortType == constant.SortTypeName {
+			c = strings.Compare(a.Title, b.Title)
+		} else {
+			c = cmp.Compare(key(a), key(b))
+		}
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		if !asc {
+			c = -c
+		}
+		return c
+	})
+}
+
+// paginate 返回从 start 开始的 n 个元素，next 为下一页的起始位置，没有更多时为0
+func paginate[T any](items []T, start, n int) ([]T, int) {
+	if start >= len(items) {
+		return nil, 0
+	}
+	end := min(start+n, len(items))
+	if end == len(items) {
+		return items[start:end], 0
+	}
+	return items[start:end], end
+}
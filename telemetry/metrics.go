@@ -0,0 +1,63 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// RequestInfo 一次 HTTP 请求（含重试）的指标数据
+type RequestInfo struct {
+	Operation  string        // SDK 操作名，如 ListDocuments；不经过 SDK 方法发出的请求为 UnknownOperation
+	Method     string        // 请求方法
+	StatusCode int           // HTTP 状态码，请求失败时为0
+	Ret        int           // 响应中的 ret 码，HasRet 为 false 时无意义
+	HasRet     bool          // 响应是否包含 ret 字段
+	Latency    time.Duration // 从发送请求到收到响应头的耗时
+	Attempt    int           // 第几次尝试，从1开始
+	Err        error         // 网络错误
+}
+
+// Metrics 指标采集扩展点，实现需要并发安全
+//
+// Prometheus 实现位于 telemetry/prometheus 子包。
+type Metrics interface {
+	// ObserveRequest 记录一次 HTTP 请求，用于统计请求数、延迟和 ret 分布
+	ObserveRequest(info RequestInfo)
+	// ObserveTokenRefresh 记录一次令牌刷新，err 为刷新失败的原因
+	ObserveTokenRefresh(err error)
+	// ObserveExport 记录一次导出任务从创建到完成的耗时
+	ObserveExport(exportType string, duration time.Duration)
+	// AddDownloadedBytes 记录下载的字节数
+	AddDownloadedBytes(n int64)
+}
+
+// NopMetrics 不记录任何指标的 Metrics 实现，是 config.Config 的默认值
+type NopMetrics struct{}
+
+// ObserveRequest 实现 Metrics 接口
+func (NopMetrics) ObserveRequest(RequestInfo) {}
+
+// ObserveTokenRefresh 实现 Metrics 接口
+func (NopMetrics) ObserveTokenRefresh(error) {}
+
+// ObserveExport 实现 Metrics 接口
+func (NopMetrics) ObserveExport(string, time.Duration) {}
+
+// AddDownloadedBytes 实现 Metrics 接口
+func (NopMetrics) AddDownloadedBytes(int64) {}
+
+// UnknownOperation 不经过 SDK 方法发出的请求使用的操作名，避免以请求路径作为指标标签导致基数无限增长
+const UnknownOperation = "unknown"
+
+type operationKey struct{}
+
+// ContextWithOperation 返回携带 SDK 操作名的 ctx，指标按操作名区分请求
+func ContextWithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// OperationFromContext 返回 ctx 中的 SDK 操作名，不存在时返回空字符串
+func OperationFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
@@ -0,0 +1,112 @@
+// Package otel 提供基于 OpenTelemetry 的 telemetry.Tracer 实现。
+//
+//	docClient := client.NewClient(
+//	    config.WithTracer(otel.NewTracer()),
+//	)
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+// ScopeName 创建 OpenTelemetry Tracer 时使用的 instrumentation scope
+const ScopeName = "github.com/chinahtl/tencent-doc-sdk"
+
+// Tracer 基于 OpenTelemetry 的 telemetry.Tracer 实现
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// Option Tracer 配置选项
+type Option func(*Tracer)
+
+// WithTracerProvider 设置 TracerProvider，默认使用 otel.GetTracerProvider()
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		t.tracer = provider.Tracer(ScopeName)
+	}
+}
+
+// WithPropagator 设置链路上下文传播方式，默认使用 otel.GetTextMapPropagator()
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(t *Tracer) {
+		t.propagator = propagator
+	}
+}
+
+// NewTracer 创建 OpenTelemetry Tracer
+func NewTracer(opts ...Option) *Tracer {
+	t := &Tracer{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.tracer == nil {
+		t.tracer = otel.GetTracerProvider().Tracer(ScopeName)
+	}
+	if t.propagator == nil {
+		t.propagator = otel.GetTextMapPropagator()
+	}
+	return t
+}
+
+// Start 实现 telemetry.Tracer 接口，span 名称为 "tencentdoc.<operation>"
+func (t *Tracer) Start(ctx context.Context, operation string, attrs ...telemetry.Attribute) (context.Context, telemetry.Span) {
+	ctx, span := t.tracer.Start(ctx, "tencentdoc."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convert(attrs)...),
+	)
+	return ctx, &otelSpan{span: span}
+}
+
+// Inject 实现 telemetry.Tracer 接口
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttributes(attrs ...telemetry.Attribute) {
+	s.span.SetAttributes(convert(attrs)...)
+}
+
+func (s *otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+func convert(attrs []telemetry.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(attr.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(attr.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(attr.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(attr.Key, v))
+		case float64:
+			kvs = append(kvs, attribute.Float64(attr.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(attr.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,118 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/chinahtl/tencent-doc-sdk/client"
+	"github.com/chinahtl/tencent-doc-sdk/config"
+	"github.com/chinahtl/tencent-doc-sdk/model"
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+	"github.com/chinahtl/tencent-doc-sdk/util"
+)
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracer(WithTracerProvider(provider), WithPropagator(propagation.TraceContext{}))
+
+	ctx, span := tracer.Start(context.Background(), "ExportDocument",
+		telemetry.String(telemetry.AttrFileID, "f1"),
+		telemetry.String(telemetry.AttrExportType, "pdf"),
+	)
+
+	header := make(http.Header)
+	tracer.Inject(ctx, header)
+	if header.Get("Traceparent") == "" {
+		t.Fatalf("traceparent not injected: %v", header)
+	}
+
+	span.SetAttributes(telemetry.Int(telemetry.AttrRetries, 2), telemetry.Int(telemetry.AttrRet, 400009))
+	span.End(errors.New("rate limited"))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	got := spans[0]
+	if got.Name() != "tencentdoc.ExportDocument" {
+		t.Fatalf("span name = %q", got.Name())
+	}
+	if got.Status().Code != codes.Error {
+		t.Fatalf("span status = %v, want Error", got.Status())
+	}
+
+	want := map[attribute.Key]attribute.Value{
+		telemetry.AttrFileID:     attribute.StringValue("f1"),
+		telemetry.AttrExportType: attribute.StringValue("pdf"),
+		telemetry.AttrRetries:    attribute.IntValue(2),
+		telemetry.AttrRet:        attribute.IntValue(400009),
+	}
+	for _, kv := range got.Attributes() {
+		if v, ok := want[kv.Key]; ok {
+			if kv.Value != v {
+				t.Errorf("attribute %s = %v, want %v", kv.Key, kv.Value.Emit(), v.Emit())
+			}
+			delete(want, kv.Key)
+		}
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing attributes: %v", want)
+	}
+}
+
+// failingTransport 所有请求都返回网络错误
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTracerErrorOmitsAccessToken(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := client.NewClient(
+		config.WithHttpTransport(failingTransport{}),
+		config.WithRetryPolicy(&util.RetryPolicy{MaxAttempts: 1}),
+		config.WithTracer(NewTracer(WithTracerProvider(provider))),
+		config.WithInitialToken(&model.Token{AccessToken: "secret-token"}),
+	)
+
+	// GetUserInfo 通过查询参数传递 access_token
+	if _, err := c.GetUserInfo(context.Background()); err == nil {
+		t.Fatal("GetUserInfo() error = nil, want network error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) == 0 {
+		t.Fatal("no spans recorded")
+	}
+	for _, span := range spans {
+		if span.Status().Code != codes.Error {
+			t.Errorf("span %s status = %v, want Error", span.Name(), span.Status())
+		}
+		if strings.Contains(span.Status().Description, "secret-token") {
+			t.Errorf("span %s status contains access token: %q", span.Name(), span.Status().Description)
+		}
+		for _, event := range span.Events() {
+			for _, kv := range event.Attributes {
+				if strings.Contains(kv.Value.Emit(), "secret-token") {
+					t.Errorf("span %s event %s attribute %s contains access token", span.Name(), event.Name, kv.Key)
+				}
+			}
+		}
+	}
+}
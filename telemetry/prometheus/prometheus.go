@@ -0,0 +1,158 @@
+// Package prometheus 提供基于 Prometheus 的 telemetry.Metrics 实现。
+//
+//	metrics := prometheus.NewMetrics()
+//	registry.MustRegister(metrics)
+//
+//	docClient := client.NewClient(
+//	    config.WithMetrics(metrics),
+//	)
+//
+// 导出的指标（默认命名空间为 tencentdoc）：
+//   - tencentdoc_requests_total{operation,method,code,ret}：HTTP 请求数（含重试），code 在请求失败时为 error，响应不含 ret 时 ret 为空
+//   - tencentdoc_request_duration_seconds{operation,method}：HTTP 请求延迟
+//   - tencentdoc_token_refreshes_total{result}：令牌刷新次数，result 为 success 或 failure
+//   - tencentdoc_export_duration_seconds{export_type}：导出任务从创建到完成的耗时
+//   - tencentdoc_downloaded_bytes_total：下载的字节数
+package prometheus
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+// Metrics 基于 Prometheus 的 telemetry.Metrics 实现，同时实现 prometheus.Collector
+type Metrics struct {
+	requests   *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	refreshes  *prometheus.CounterVec
+	exports    *prometheus.HistogramVec
+	downloaded prometheus.Counter
+}
+
+type options struct {
+	namespace      string
+	requestBuckets []float64
+	exportBuckets  []float64
+}
+
+// Option Metrics 配置选项
+type Option func(*options)
+
+// WithNamespace 设置指标命名空间，默认为 tencentdoc
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithRequestBuckets 设置请求延迟直方图的分桶（秒），默认为 prometheus.DefBuckets
+func WithRequestBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.requestBuckets = buckets
+	}
+}
+
+// WithExportBuckets 设置导出耗时直方图的分桶（秒），默认为1秒到约17分钟的指数分桶
+func WithExportBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.exportBuckets = buckets
+	}
+}
+
+// NewMetrics 创建 Prometheus 指标，需要注册到 prometheus.Registerer 后才会被采集
+func NewMetrics(opts ...Option) *Metrics {
+	o := &options{
+		namespace:      "tencentdoc",
+		requestBuckets: prometheus.DefBuckets,
+		exportBuckets:  prometheus.ExponentialBuckets(1, 2, 11),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests sent to Tencent Docs, including retries.",
+		}, []string{"operation", "method", "code", "ret"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests sent to Tencent Docs.",
+			Buckets:   o.requestBuckets,
+		}, []string{"operation", "method"}),
+		refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "token_refreshes_total",
+			Help:      "Total number of access token refreshes.",
+		}, []string{"result"}),
+		exports: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "export_duration_seconds",
+			Help:      "Time from creating an export task to its completion.",
+			Buckets:   o.exportBuckets,
+		}, []string{"export_type"}),
+		downloaded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "downloaded_bytes_total",
+			Help:      "Total number of bytes downloaded from COS.",
+		}),
+	}
+}
+
+// ObserveRequest 实现 telemetry.Metrics 接口
+func (m *Metrics) ObserveRequest(info telemetry.RequestInfo) {
+	code := "error"
+	if info.Err == nil {
+		code = strconv.Itoa(info.StatusCode)
+	}
+	ret := ""
+	if info.HasRet {
+		ret = strconv.Itoa(info.Ret)
+	}
+
+	m.requests.WithLabelValues(info.Operation, info.Method, code, ret).Inc()
+	m.latency.WithLabelValues(info.Operation, info.Method).Observe(info.Latency.Seconds())
+}
+
+// ObserveTokenRefresh 实现 telemetry.Metrics 接口
+func (m *Metrics) ObserveTokenRefresh(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.refreshes.WithLabelValues(result).Inc()
+}
+
+// ObserveExport 实现 telemetry.Metrics 接口
+func (m *Metrics) ObserveExport(exportType string, duration time.Duration) {
+	m.exports.WithLabelValues(exportType).Observe(duration.Seconds())
+}
+
+// AddDownloadedBytes 实现 telemetry.Metrics 接口
+func (m *Metrics) AddDownloadedBytes(n int64) {
+	m.downloaded.Add(float64(n))
+}
+
+// Describe 实现 prometheus.Collector 接口
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.latency.Describe(ch)
+	m.refreshes.Describe(ch)
+	m.exports.Describe(ch)
+	m.downloaded.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector 接口
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.latency.Collect(ch)
+	m.refreshes.Collect(ch)
+	m.exports.Collect(ch)
+	m.downloaded.Collect(ch)
+}
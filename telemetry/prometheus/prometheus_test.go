@@ -0,0 +1,56 @@
+package prometheus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := NewMetrics()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(m)
+
+	m.ObserveRequest(telemetry.RequestInfo{Operation: "ListDocuments", Method: "GET", StatusCode: 200, HasRet: true, Latency: 20 * time.Millisecond})
+	m.ObserveRequest(telemetry.RequestInfo{Operation: "ListDocuments", Method: "GET", StatusCode: 200, Ret: 400009, HasRet: true, Latency: 10 * time.Millisecond})
+	m.ObserveRequest(telemetry.RequestInfo{Operation: "ListDocuments", Method: "GET", Err: errors.New("reset")})
+	m.ObserveTokenRefresh(nil)
+	m.ObserveTokenRefresh(errors.New("invalid refresh token"))
+	m.ObserveExport("pdf", 3*time.Second)
+	m.AddDownloadedBytes(1024)
+
+	want := `
+# HELP tencentdoc_requests_total Total number of HTTP requests sent to Tencent Docs, including retries.
+# TYPE tencentdoc_requests_total counter
+tencentdoc_requests_total{code="200",method="GET",operation="ListDocuments",ret="0"} 1
+tencentdoc_requests_total{code="200",method="GET",operation="ListDocuments",ret="400009"} 1
+tencentdoc_requests_total{code="error",method="GET",operation="ListDocuments",ret=""} 1
+# HELP tencentdoc_token_refreshes_total Total number of access token refreshes.
+# TYPE tencentdoc_token_refreshes_total counter
+tencentdoc_token_refreshes_total{result="failure"} 1
+tencentdoc_token_refreshes_total{result="success"} 1
+# HELP tencentdoc_downloaded_bytes_total Total number of bytes downloaded from COS.
+# TYPE tencentdoc_downloaded_bytes_total counter
+tencentdoc_downloaded_bytes_total 1024
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"tencentdoc_requests_total", "tencentdoc_token_refreshes_total", "tencentdoc_downloaded_bytes_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := testutil.CollectAndCount(m, "tencentdoc_request_duration_seconds"); n != 1 {
+		t.Fatalf("request_duration_seconds series = %d, want 1", n)
+	}
+	if n := testutil.CollectAndCount(m, "tencentdoc_export_duration_seconds"); n != 1 {
+		t.Fatalf("export_duration_seconds series = %d, want 1", n)
+	}
+}
@@ -0,0 +1,69 @@
+// Package telemetry 定义 SDK 的可观测性扩展点。
+//
+// Tracer 为每次 SDK 操作（如 ListDocuments、ExportDocument、文件下载）创建一个 span，
+// 并将链路上下文注入到发出的 HTTP 请求中。OpenTelemetry 适配实现位于 telemetry/otel 子包。
+//
+// Metrics 记录请求数、延迟、ret 分布、令牌刷新、导出耗时和下载字节数，
+// Prometheus 实现位于 telemetry/prometheus 子包。
+package telemetry
+
+import (
+	"context"
+	"net/http"
+)
+
+// span 属性名
+const (
+	AttrFileID      = "tencentdoc.file_id"      // 文件ID
+	AttrFolderID    = "tencentdoc.folder_id"    // 文件夹ID
+	AttrExportType  = "tencentdoc.export_type"  // 导出类型
+	AttrOperationID = "tencentdoc.operation_id" // 导出任务ID
+	AttrRet         = "tencentdoc.ret"          // 接口返回的 ret 码
+	AttrRetries     = "tencentdoc.retries"      // 重试次数，不含首次请求
+	AttrPolls       = "tencentdoc.polls"        // 查询导出进度的次数
+)
+
+// Attribute span 属性，Value 支持 string、bool、int、int64 和 float64
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String 创建字符串属性
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int 创建整数属性
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer 链路追踪扩展点，实现需要并发安全
+type Tracer interface {
+	// Start 以 ctx 中的 span 为父 span 创建新的 span，operation 为 SDK 操作名，如 ListDocuments
+	Start(ctx context.Context, operation string, attrs ...Attribute) (context.Context, Span)
+	// Inject 将 ctx 中的链路上下文写入请求头，每次 HTTP 请求（含重试）调用一次
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span 一次 SDK 操作
+type Span interface {
+	// SetAttributes 设置属性，同名属性会被覆盖
+	SetAttributes(attrs ...Attribute)
+	// End 结束 span，err 不为空时将 span 标记为失败
+	End(err error)
+}
+
+type spanKey struct{}
+
+// ContextWithSpan 返回携带 span 的 ctx，SDK 内部使用它在发送请求时更新当前操作的属性
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext 返回 ctx 中由 ContextWithSpan 设置的 span，不存在时返回 nil
+func SpanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanKey{}).(Span)
+	return span
+}
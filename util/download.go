@@ -1,12 +1,14 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -37,16 +39,29 @@ import (
 //  4. 创建本地目录（如果不存在）。
 //  5. 将文件流式写入本地路径。
 //  6. 返回下载文件的完整路径。
+//
+// 该函数使用默认 HTTP 客户端且无法取消，建议使用 client.Client 的 DownloadFromCOS 方法，
+// 以应用客户端配置的 Transport、超时和拦截器。
 func DownloadFromCOS(fileURL, saveDir string) (string, error) {
-	resp, err := http.Get(fileURL)
+	return DownloadFile(context.Background(), NewExecutor(nil), fileURL, saveDir)
+}
+
+// DownloadFile 使用执行器 e 下载文件到本地目录 saveDir，返回文件的完整本地路径。
+//
+// ctx 用于取消下载，取消时已写入的不完整文件会被删除。其余行为同 DownloadFromCOS。
+func DownloadFile(ctx context.Context, e *Executor, fileURL, saveDir string) (string, error) {
+	resp, err := e.Stream(ctx, &Request{Endpoint: fileURL})
 	if err != nil {
 		return "", fmt.Errorf("下载请求失败: %w", err)
 	}
-	defer resp.Body.Close()
+	return SaveResponse(resp, saveDir)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
-	}
+// SaveResponse 将 Stream 返回的下载响应写入本地目录 saveDir 并关闭响应体，返回文件的完整本地路径。
+//
+// 文件名从响应头 Content-Disposition 中解析，写入失败时已写入的不完整文件会被删除。
+func SaveResponse(resp *http.Response, saveDir string) (string, error) {
+	defer resp.Body.Close()
 
 	// 获取文件名
 	fileName, err := getFileName(resp)
@@ -76,12 +91,57 @@ func DownloadFromCOS(fileURL, saveDir string) (string, error) {
 
 	// 流式写入文件
 	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		os.Remove(fullPath)
 		return "", fmt.Errorf("文件写入失败: %w", err)
 	}
 
 	return fullPath, nil
 }
 
+// Download 打开的下载流，读取完毕后需关闭 Body
+type Download struct {
+	Body        io.ReadCloser
+	FileName    string // 从 Content-Disposition 解析的文件名，无法解析时使用下载地址的最后一段
+	ContentType string // 响应的 Content-Type，缺失或为 application/octet-stream 时根据文件扩展名推断
+	Size        int64  // 响应的 Content-Length，未知时为 -1
+}
+
+// OpenDownload 使用执行器 e 发起下载请求并返回响应体，不写入本地文件，适用于转发到其他 io.Writer 的场景。
+//
+// 请求在 ctx 结束时中止，调用方需保证读取响应体期间 ctx 有效。
+func OpenDownload(ctx context.Context, e *Executor, fileURL string) (*Download, error) {
+	resp, err := e.Stream(ctx, &Request{Endpoint: fileURL})
+	if err != nil {
+		return nil, fmt.Errorf("下载请求失败: %w", err)
+	}
+
+	fileName, err := getFileName(resp)
+	if err != nil {
+		fileName = ""
+		if u, parseErr := url.Parse(fileURL); parseErr == nil && strings.Trim(u.Path, "/") != "" {
+			fileName = sanitizeFileName(path.Base(u.Path))
+		}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "" || mediaType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			contentType = byExt
+		}
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &Download{
+		Body:        resp.Body,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        resp.ContentLength,
+	}, nil
+}
+
 // getFileName 获取文件名
 func getFileName(resp *http.Response) (string, error) {
 	disposition := resp.Header.Get("Content-Disposition")
@@ -4,230 +4,358 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
 )
 
-// PostForm 表单POST请求
-func PostForm(ctx context.Context, client *http.Client, endpoint string, form url.Values, result interface{}) error {
-	req, err := newRequest(ctx, http.MethodPost, endpoint, nil, form, "")
-	if err != nil {
-		return err
+// requestIDHeaders 可能携带服务端请求ID的响应头，按顺序取第一个非空值
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Trace-Id", "Trace-Id"}
+
+// RequestID 从响应头中提取服务端请求ID
+func RequestID(header http.Header) string {
+	for _, key := range requestIDHeaders {
+		if id := header.Get(key); id != "" {
+			return id
+		}
 	}
+	return ""
+}
 
-	return doRequest(client, req, result)
+// Request 描述一次接口请求
+//
+// Form 和 JSON 最多设置一个，分别以 application/x-www-form-urlencoded 和 application/json 格式发送。
+type Request struct {
+	Method   string      // 请求方法，默认为 GET
+	Endpoint string      // 请求地址，设置 Query 时会替换其中的查询参数
+	Query    url.Values  // 查询参数
+	Form     url.Values  // 表单请求体
+	JSON     interface{} // JSON 请求体
+	Header   http.Header // 额外的请求头
 }
 
-func newRequest(ctx context.Context, method, endpoint string, query url.Values, form url.Values, token string) (*http.Request, error) {
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("invalid endpoint: %w", err)
-	}
+// Response 接口响应，除 Stream 外响应体均已被完整读取
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	RequestID  string        // 服务端请求ID
+	Duration   time.Duration // 从发送请求到读取完响应体的耗时，包含重试
+	Attempts   int           // 实际发送的请求次数，包含重试
+}
 
-	if query != nil {
-		u.RawQuery = query.Encode()
-	}
+// Envelope 腾讯文档 OpenAPI 通用响应结构
+type Envelope[T any] struct {
+	Ret  int    `json:"ret"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
 
-	var body io.Reader
-	contentType := ""
-	if form != nil {
-		body = bytes.NewBufferString(form.Encode())
-		contentType = "application/x-www-form-urlencoded"
+// Interceptor 请求拦截器，每次 Executor 调用执行一次（不含传输层重试）
+//
+// BeforeRequest 在请求发出前按注册顺序执行，可修改请求，返回错误时终止请求；
+// AfterResponse 在收到响应或请求失败后按注册的逆序执行，resp 在请求失败时为 nil。
+type Interceptor interface {
+	BeforeRequest(req *http.Request) error
+	AfterResponse(req *http.Request, resp *Response, err error)
+}
+
+// InterceptorFuncs 使用函数实现 Interceptor，未设置的函数会被跳过
+type InterceptorFuncs struct {
+	Before func(req *http.Request) error
+	After  func(req *http.Request, resp *Response, err error)
+}
+
+// BeforeRequest 实现 Interceptor 接口
+func (f InterceptorFuncs) BeforeRequest(req *http.Request) error {
+	if f.Before == nil {
+		return nil
 	}
+	return f.Before(req)
+}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
-	if err != nil {
-		return nil, fmt.Errorf("create request failed: %w", err)
+// AfterResponse 实现 Interceptor 接口
+func (f InterceptorFuncs) AfterResponse(req *http.Request, resp *Response, err error) {
+	if f.After != nil {
+		f.After(req, resp, err)
 	}
+}
 
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+// Executor 统一的请求执行器，负责构造请求、执行拦截器链、处理错误状态码并解析响应
+//
+// Executor 并发安全。
+type Executor struct {
+	client       *http.Client
+	interceptors []Interceptor
+}
+
+// NewExecutor 创建请求执行器，client 为空时使用 http.DefaultClient
+func NewExecutor(client *http.Client, interceptors ...Interceptor) *Executor {
+	if client == nil {
+		client = http.DefaultClient
 	}
+	return &Executor{client: client, interceptors: interceptors}
+}
+
+// HTTPClient 返回执行器使用的 HTTP 客户端
+func (e *Executor) HTTPClient() *http.Client {
+	return e.client
+}
 
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+// Do 发送请求并将 JSON 响应解析到 result，result 为空时不解析
+//
+// HTTP 状态码非200时返回 *model.APIError。
+func (e *Executor) Do(ctx context.Context, req *Request, result interface{}) (*Response, error) {
+	resp, err := e.send(ctx, req)
+	if err != nil {
+		return resp, err
 	}
 
-	return req, nil
+	if result != nil {
+		if err := json.Unmarshal(resp.Body, result); err != nil {
+			return resp, decodeError(resp, err)
+		}
+	}
+	return resp, nil
 }
 
-func doRequest(client *http.Client, req *http.Request, result interface{}) error {
-	resp, err := client.Do(req)
+// DoAPI 发送 OpenAPI 请求，校验 {ret,msg,data} 响应结构后将完整响应解析到 result
+//
+// HTTP 状态码非200或 ret 非0时返回 *model.APIError。
+func (e *Executor) DoAPI(ctx context.Context, req *Request, result interface{}) (*Response, error) {
+	resp, err := e.send(ctx, req)
 	if err != nil {
-		return fmt.Errorf("http request failed: %w", err)
+		return resp, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	var envelope Envelope[json.RawMessage]
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+		return resp, decodeError(resp, err)
+	}
+	if envelope.Ret != 0 {
+		return resp, &model.APIError{
+			StatusCode: resp.StatusCode,
+			Ret:        envelope.Ret,
+			Msg:        envelope.Msg,
+			Endpoint:   req.Endpoint,
+			RequestID:  resp.RequestID,
+		}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-		return fmt.Errorf("decode response failed: %w", err)
+	if result != nil {
+		if err := json.Unmarshal(resp.Body, result); err != nil {
+			return resp, decodeError(resp, err)
+		}
 	}
+	return resp, nil
+}
 
-	return nil
+// Call 发送 OpenAPI 请求并返回响应中的 data 字段，适用于 SDK 尚未封装的接口
+func Call[T any](ctx context.Context, e *Executor, req *Request) (*T, error) {
+	var envelope Envelope[T]
+	if _, err := e.DoAPI(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.Data, nil
 }
 
-// GetWithCustomHeaders 带自定义Header的GET请求
-func GetWithCustomHeaders(
-	ctx context.Context,
-	client *http.Client,
-	url string,
-	headers map[string]string,
-	result interface{},
-) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// Stream 发送请求并返回未读取响应体的响应，适用于文件下载等大响应，调用方负责关闭响应体
+//
+// 拦截器收到的 Response.Body 为空。HTTP 状态码非200时返回 *model.APIError。
+func (e *Executor) Stream(ctx context.Context, req *Request) (*http.Response, error) {
+	httpReq, err := e.prepare(ctx, req)
 	if err != nil {
-		return fmt.Errorf("create request failed: %w", err)
+		return nil, err
 	}
 
-	// 添加自定义Header
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+	attempts := new(int)
+	httpReq = httpReq.WithContext(context.WithValue(httpReq.Context(), attemptsKey{}, attempts))
 
-	resp, err := client.Do(req)
+	start := time.Now()
+	httpResp, err := e.client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("http request failed: %w", err)
+		err = requestError(httpReq, err)
+		e.complete(httpReq, nil, err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	resp := &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		RequestID:  RequestID(httpResp.Header),
+		Duration:   time.Since(start),
+		Attempts:   max(*attempts, 1),
 	}
+	e.complete(httpReq, resp, nil)
 
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-		return fmt.Errorf("decode response failed: %w", err)
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		resp.Body, _ = io.ReadAll(io.LimitReader(httpResp.Body, 64<<10))
+		return nil, newStatusError(httpReq, resp)
 	}
-
-	return nil
+	return httpResp, nil
 }
 
-// PostFormWithHeaders 发送带自定义Header的表单POST请求
-func PostFormWithHeaders(
-	ctx context.Context,
-	client *http.Client,
-	url string,
-	form url.Values,
-	headers map[string]string,
-	result interface{},
-) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(form.Encode()))
+// send 执行拦截器链并发送请求，返回已读取响应体的响应
+func (e *Executor) send(ctx context.Context, req *Request) (*Response, error) {
+	httpReq, err := e.prepare(ctx, req)
 	if err != nil {
-		return fmt.Errorf("create request failed: %w", err)
+		return nil, err
 	}
 
-	// 设置请求头
-	for k, v := range headers {
-		req.Header.Set(k, v)
-	}
-
-	resp, err := client.Do(req)
+	resp, err := e.roundTrip(httpReq)
+	e.complete(httpReq, resp, err)
 	if err != nil {
-		return fmt.Errorf("http request failed: %w", err)
+		return resp, err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+		return resp, newStatusError(httpReq, resp)
+	}
+	return resp, nil
+}
+
+// prepare 构造请求并按顺序执行 BeforeRequest
+func (e *Executor) prepare(ctx context.Context, req *Request) (*http.Request, error) {
+	httpReq, err := newRequest(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-		return fmt.Errorf("decode response failed: %w", err)
+	for _, interceptor := range e.interceptors {
+		if err := interceptor.BeforeRequest(httpReq); err != nil {
+			return nil, err
+		}
 	}
+	return httpReq, nil
+}
 
-	return nil
+// complete 按逆序执行 AfterResponse
+func (e *Executor) complete(req *http.Request, resp *Response, err error) {
+	for i := len(e.interceptors) - 1; i >= 0; i-- {
+		e.interceptors[i].AfterResponse(req, resp, err)
+	}
 }
 
-// PostJSONWithAuth 带认证的JSON POST请求
-func PostJSONWithAuth(
-	ctx context.Context,
-	client *http.Client,
-	endpoint string,
-	body interface{},
-	token string,
-	result interface{},
-) error {
-	jsonBody, err := json.Marshal(body)
+// roundTrip 发送请求并读取响应体，统计经过 RetryTransport 的实际请求次数
+func (e *Executor) roundTrip(req *http.Request) (*Response, error) {
+	attempts := new(int)
+	req = req.WithContext(context.WithValue(req.Context(), attemptsKey{}, attempts))
+
+	start := time.Now()
+
+	httpResp, err := e.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("marshal json failed: %w", err)
+		return nil, requestError(req, err)
 	}
+	defer httpResp.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonBody))
+	body, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		return fmt.Errorf("create request failed: %w", err)
+		return nil, fmt.Errorf("read response body failed: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	return &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       body,
+		RequestID:  RequestID(httpResp.Header),
+		Duration:   time.Since(start),
+		Attempts:   max(*attempts, 1),
+	}, nil
+}
 
-	resp, err := client.Do(req)
+func newRequest(ctx context.Context, req *Request) (*http.Request, error) {
+	u, err := url.Parse(req.Endpoint)
 	if err != nil {
-		return fmt.Errorf("http request failed: %w", err)
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	if len(req.Query) > 0 {
+		u.RawQuery = req.Query.Encode()
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-		return fmt.Errorf("decode response failed: %w", err)
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
 	}
 
-	return nil
-}
+	var body io.Reader
+	contentType := ""
+	switch {
+	case req.Form != nil && req.JSON != nil:
+		return nil, fmt.Errorf("request cannot have both form and json body")
+	case req.Form != nil:
+		body = strings.NewReader(req.Form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	case req.JSON != nil:
+		data, err := json.Marshal(req.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("marshal json failed: %w", err)
+		}
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	}
 
-// HTTPGet 发送HTTP GET请求并将响应解析为JSON。
-//
-// ctx 用于控制请求的上下文，可用于超时控制和取消。
-//
-// url 是请求的完整URL地址。
-//
-// result 是用于存储JSON响应的结构体指针。
-//
-// 返回错误信息，可能的错误包括：
-//   - 创建请求失败
-//   - 发送请求失败
-//   - 读取响应失败
-//   - JSON解析失败
-func HTTPGet(ctx context.Context, url string, result interface{}) error {
-	// 创建带有上下文的请求
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("create request failed: %w", err)
 	}
 
-	// 发送请求
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	for key, values := range req.Header {
+		httpReq.Header[key] = append([]string(nil), values...)
 	}
-	defer resp.Body.Close()
 
-	// 读取响应体
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+	return httpReq, nil
+}
+
+// decodeError 包装响应解析错误，附带服务端请求ID
+func decodeError(resp *Response, err error) error {
+	if resp.RequestID != "" {
+		return fmt.Errorf("decode response failed (request_id=%s): %w", resp.RequestID, err)
 	}
+	return fmt.Errorf("decode response failed: %w", err)
+}
 
-	// 检查状态码
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(body))
+// requestError 包装发送请求失败的错误，并去掉 *url.Error 中的查询参数
+func requestError(req *http.Request, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		endpoint := *req.URL
+		endpoint.RawQuery = "" // 查询参数中可能包含 access_token
+		urlErr.URL = endpoint.String()
 	}
+	return fmt.Errorf("http request failed: %w", err)
+}
 
-	// 解析JSON响应
-	if err := json.Unmarshal(body, result); err != nil {
-		return fmt.Errorf("failed to parse JSON response: %w", err)
+// newStatusError 将非200响应转换为 *model.APIError，响应体为 {ret,msg} 格式时解析其中的错误信息
+func newStatusError(req *http.Request, resp *Response) error {
+	endpoint := *req.URL
+	endpoint.RawQuery = "" // 查询参数中可能包含 access_token
+
+	apiErr := &model.APIError{
+		StatusCode: resp.StatusCode,
+		Msg:        strings.TrimSpace(string(resp.Body)),
+		Endpoint:   endpoint.String(),
+		RequestID:  resp.RequestID,
+	}
+
+	var envelope struct {
+		Ret int    `json:"ret"`
+		Msg string `json:"msg"`
+	}
+	if json.Unmarshal(resp.Body, &envelope) == nil && envelope.Ret != 0 {
+		apiErr.Ret = envelope.Ret
+		apiErr.Msg = envelope.Msg
 	}
 
-	return nil
+	return apiErr
 }
@@ -0,0 +1,162 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/model"
+)
+
+func TestExecutorInterceptorChain(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "abc" {
+			t.Errorf("interceptor header missing")
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("k") != "v" {
+			t.Errorf("form body = %v", r.PostForm)
+		}
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(`{"ret":0,"msg":"ok","data":{"name":"doc"}}`))
+	}))
+	defer srv.Close()
+
+	var order []string
+	record := func(name string) Interceptor {
+		return InterceptorFuncs{
+			Before: func(req *http.Request) error {
+				order = append(order, "before "+name)
+				req.Header.Set("X-Trace", "abc")
+				return nil
+			},
+			After: func(req *http.Request, resp *Response, err error) {
+				order = append(order, "after "+name)
+				if resp.RequestID != "req-1" {
+					t.Errorf("RequestID = %q", resp.RequestID)
+				}
+			},
+		}
+	}
+
+	e := NewExecutor(srv.Client(), record("a"), record("b"))
+	data, err := Call[struct {
+		Name string `json:"name"`
+	}](context.Background(), e, &Request{
+		Method:   http.MethodPost,
+		Endpoint: srv.URL,
+		Form:     url.Values{"k": []string{"v"}},
+	})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if data.Name != "doc" {
+		t.Fatalf("Call() data = %+v", data)
+	}
+
+	want := []string{"before a", "before b", "after b", "after a"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("interceptor order = %v, want %v", order, want)
+	}
+}
+
+func TestExecutorAbortsOnBeforeRequestError(t *testing.T) {
+	t.Parallel()
+
+	abort := errors.New("abort")
+	e := NewExecutor(nil, InterceptorFuncs{Before: func(*http.Request) error { return abort }})
+
+	_, err := e.Do(context.Background(), &Request{Endpoint: "http://127.0.0.1:0"}, nil)
+	if !errors.Is(err, abort) {
+		t.Fatalf("Do() error = %v, want %v", err, abort)
+	}
+}
+
+func TestExecutorDoAPIReturnsAPIError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-2")
+		w.Write([]byte(`{"ret":400004,"msg":"not found"}`))
+	}))
+	defer srv.Close()
+
+	e := NewExecutor(srv.Client())
+	var result map[string]interface{}
+	_, err := e.DoAPI(context.Background(), &Request{Endpoint: srv.URL + "/drive/v2/x"}, &result)
+
+	var apiErr *model.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("DoAPI() error = %v, want *model.APIError", err)
+	}
+	if apiErr.Ret != 400004 || apiErr.RequestID != "req-2" || apiErr.Endpoint != srv.URL+"/drive/v2/x" {
+		t.Fatalf("APIError = %+v", apiErr)
+	}
+	if result != nil {
+		t.Fatalf("result decoded on error: %v", result)
+	}
+}
+
+func TestExecutorReportsAttempts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-3")
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`not json`))
+	}))
+	defer srv.Close()
+
+	e := NewExecutor(&http.Client{Transport: NewRetryTransport(nil, testRetryPolicy())})
+	var result map[string]interface{}
+	resp, err := e.Do(context.Background(), &Request{Endpoint: srv.URL}, &result)
+	if err == nil || !strings.Contains(err.Error(), "request_id=req-3") {
+		t.Fatalf("Do() error = %v, want decode error with request id", err)
+	}
+	if resp == nil || resp.Attempts != 3 || resp.RequestID != "req-3" {
+		t.Fatalf("Do() response = %+v, want 3 attempts", resp)
+	}
+
+	resp, err = NewExecutor(srv.Client()).Do(context.Background(), &Request{Endpoint: srv.URL}, nil)
+	if err != nil || resp.Attempts != 1 {
+		t.Fatalf("Do() without retry = %+v, %v", resp, err)
+	}
+}
+
+// failingTransport 所有请求都返回网络错误
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestExecutorErrorOmitsQuery(t *testing.T) {
+	t.Parallel()
+
+	e := NewExecutor(&http.Client{Transport: failingTransport{}})
+	req := &Request{Endpoint: "https://docs.qq.com/oauth/v2/userinfo", Query: url.Values{"access_token": {"secret-token"}}}
+
+	_, err := e.Do(context.Background(), req, nil)
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("Do() error = %v, want *url.Error without access_token", err)
+	}
+	if urlErr.URL != req.Endpoint {
+		t.Fatalf("url.Error.URL = %q, want %q", urlErr.URL, req.Endpoint)
+	}
+
+	if _, err := e.Stream(context.Background(), req); err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("Stream() error = %v, want error without access_token", err)
+	}
+}
@@ -0,0 +1,161 @@
+package util
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redacted 敏感字段在日志中的替代值
+const redacted = "[REDACTED]"
+
+// sensitiveKeys 需要脱敏的请求头、查询参数和表单字段，不区分大小写
+var sensitiveKeys = map[string]bool{
+	"access-token":  true,
+	"access_token":  true,
+	"authorization": true,
+	"client_secret": true,
+	"code":          true,
+	"refresh_token": true,
+}
+
+// IsSensitiveKey 判断请求头、查询参数或表单字段是否包含令牌、密钥等敏感信息，不区分大小写
+func IsSensitiveKey(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// LoggingTransport 使用 slog 记录每次 HTTP 请求的 http.RoundTripper
+//
+// 每次尝试（含重试）记录一条日志，包含 method、endpoint、status、ret、latency、attempt 等字段；
+// 请求成功且 ret 为0时级别为 Info，否则为 Warn，网络错误为 Error。
+// Debug 级别下额外记录请求头和表单请求体。
+// Access-Token、client_secret、code、refresh_token 等字段的值始终被替换为 [REDACTED]。
+type LoggingTransport struct {
+	Base   http.RoundTripper
+	Logger *slog.Logger
+}
+
+// NewLoggingTransport 创建日志 Transport，base 为空时使用 http.DefaultTransport
+func NewLoggingTransport(base http.RoundTripper, logger *slog.Logger) *LoggingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &LoggingTransport{Base: base, Logger: logger}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", redactURL(req.URL)),
+		slog.Int("attempt", attemptFrom(ctx)),
+	}
+	if t.Logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.Any("request_header", redactHeader(req.Header)))
+		if form, ok := peekForm(req); ok {
+			attrs = append(attrs, slog.String("request_body", form))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.Base.RoundTrip(req)
+	attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		t.Logger.LogAttrs(ctx, slog.LevelError, "tencent-doc request failed", attrs...)
+		return nil, err
+	}
+
+	level := slog.LevelInfo
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if id := RequestID(resp.Header); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if ret, ok := peekRet(resp); ok {
+		attrs = append(attrs, slog.Int("ret", ret))
+		if ret != 0 {
+			level = slog.LevelWarn
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		level = slog.LevelWarn
+	}
+
+	t.Logger.LogAttrs(ctx, level, "tencent-doc request", attrs...)
+	return resp, nil
+}
+
+// redactURL 返回脱敏后的请求地址
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = encodeRedacted(redactedURL.Query())
+	return redactedURL.String()
+}
+
+func redactHeader(header http.Header) http.Header {
+	redactedHeader := header.Clone()
+	for key := range redactedHeader {
+		if IsSensitiveKey(key) {
+			redactedHeader[key] = []string{redacted}
+		}
+	}
+	return redactedHeader
+}
+
+// encodeRedacted 按 url.Values.Encode 的格式编码，敏感字段的值替换为 [REDACTED]
+func encodeRedacted(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		for _, value := range values[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			if IsSensitiveKey(key) {
+				b.WriteString(redacted)
+			} else {
+				b.WriteString(url.QueryEscape(value))
+			}
+		}
+	}
+	return b.String()
+}
+
+// peekForm 读取可重放的表单请求体并脱敏，不影响请求的发送
+func peekForm(req *http.Request) (string, bool) {
+	if req.GetBody == nil || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return "", false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", false
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return "", false
+	}
+	return encodeRedacted(values), true
+}
@@ -0,0 +1,112 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoggingTransportRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(`{"ret":0,"msg":"ok"}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	e := NewExecutor(&http.Client{Transport: NewLoggingTransport(nil, logger)})
+
+	form := url.Values{}
+	form.Set("client_id", "app")
+	form.Set("client_secret", "secret-value")
+	form.Set("code", "code-value")
+	form.Set("refresh_token", "refresh-value")
+	_, err := e.Do(context.Background(), &Request{
+		Method:   http.MethodPost,
+		Endpoint: srv.URL + "/oauth/v2/token",
+		Query:    url.Values{"access_token": {"query-token"}},
+		Form:     form,
+		Header:   http.Header{"Access-Token": {"header-token"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"secret-value", "code-value", "refresh-value", "query-token", "header-token"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q: %s", secret, out)
+		}
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log record: %v", err)
+	}
+	if record["level"] != "INFO" || record["method"] != "POST" || record["status"] != float64(200) ||
+		record["ret"] != float64(0) || record["attempt"] != float64(1) || record["request_id"] != "req-1" {
+		t.Fatalf("unexpected record: %v", record)
+	}
+	if _, ok := record["latency"]; !ok {
+		t.Fatalf("record missing latency: %v", record)
+	}
+	if !strings.Contains(record["request_body"].(string), "client_id=app") {
+		t.Fatalf("request_body = %v", record["request_body"])
+	}
+}
+
+func TestLoggingTransportRecordsAttempts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ret":0}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	transport := NewRetryTransport(NewLoggingTransport(nil, logger), testRetryPolicy())
+	e := NewExecutor(&http.Client{Transport: transport})
+
+	if _, err := e.Do(context.Background(), &Request{Endpoint: srv.URL}, nil); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log records, want 2: %s", len(lines), buf.String())
+	}
+	for i, want := range []struct {
+		level   string
+		attempt float64
+		status  float64
+	}{
+		{"WARN", 1, 503},
+		{"INFO", 2, 200},
+	} {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i]), &record); err != nil {
+			t.Fatalf("decode log record: %v", err)
+		}
+		if record["level"] != want.level || record["attempt"] != want.attempt || record["status"] != want.status {
+			t.Fatalf("record %d = %v", i, record)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+// MetricsTransport 记录每次 HTTP 请求指标的 http.RoundTripper
+//
+// 请求按 ctx 中的 SDK 操作名（见 telemetry.ContextWithOperation）区分，没有操作名时使用 telemetry.UnknownOperation。
+type MetricsTransport struct {
+	Base    http.RoundTripper
+	Metrics telemetry.Metrics
+}
+
+// NewMetricsTransport 创建指标 Transport，base 为空时使用 http.DefaultTransport
+func NewMetricsTransport(base http.RoundTripper, metrics telemetry.Metrics) *MetricsTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &MetricsTransport{Base: base, Metrics: metrics}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *MetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	info := telemetry.RequestInfo{
+		Operation: telemetry.OperationFromContext(ctx),
+		Method:    req.Method,
+		Attempt:   attemptFrom(ctx),
+	}
+	if info.Operation == "" {
+		info.Operation = telemetry.UnknownOperation
+	}
+
+	start := time.Now()
+	resp, err := t.Base.RoundTrip(req)
+	info.Latency = time.Since(start)
+
+	if err != nil {
+		info.Err = err
+		t.Metrics.ObserveRequest(info)
+		return nil, err
+	}
+
+	info.StatusCode = resp.StatusCode
+	info.Ret, info.HasRet = peekRet(resp)
+	t.Metrics.ObserveRequest(info)
+	return resp, nil
+}
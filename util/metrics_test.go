@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+// operationMetrics 记录 ObserveRequest 收到的操作名
+type operationMetrics struct {
+	telemetry.NopMetrics
+	operations []string
+}
+
+func (m *operationMetrics) ObserveRequest(info telemetry.RequestInfo) {
+	m.operations = append(m.operations, info.Operation)
+}
+
+func TestMetricsTransportOperation(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	metrics := &operationMetrics{}
+	e := NewExecutor(&http.Client{Transport: NewMetricsTransport(srv.Client().Transport, metrics)})
+
+	ctx := telemetry.ContextWithOperation(context.Background(), "ListDocuments")
+	for _, ctx := range []context.Context{ctx, context.Background()} {
+		if _, err := e.Do(ctx, &Request{Endpoint: srv.URL + "/files/f1/metadata"}, nil); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+
+	if len(metrics.operations) != 2 || metrics.operations[0] != "ListDocuments" || metrics.operations[1] != telemetry.UnknownOperation {
+		t.Fatalf("operations = %v, want [ListDocuments %s]", metrics.operations, telemetry.UnknownOperation)
+	}
+}
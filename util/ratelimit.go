@@ -0,0 +1,270 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+)
+
+// RateLimit 限流配置，QPS 为0表示不限制对应维度
+type RateLimit struct {
+	AppQPS    float64 // 应用维度每秒请求数
+	AppBurst  int     // 应用维度突发请求数，默认为 max(1, AppQPS)
+	UserQPS   float64 // 单个用户（OpenID）每秒请求数
+	UserBurst int     // 单个用户突发请求数，默认为 max(1, UserQPS)
+
+	// 自适应调整：观察到限流错误时速率乘以 BackoffFactor（默认0.5），最低降至配置速率的 MinFactor（默认0.1）；
+	// 此后每隔 RecoverInterval（默认10s）没有再被限流，速率恢复配置值的10%，直至恢复到配置值
+	BackoffFactor   float64
+	MinFactor       float64
+	RecoverInterval time.Duration
+}
+
+// RateLimiter 基于令牌桶的客户端限流器，同时限制应用维度和用户维度的请求速率
+//
+// RateLimiter 并发安全，多个客户端共享同一实例时共享限流额度。
+type RateLimiter struct {
+	cfg  RateLimit
+	app  *bucket
+	mu   sync.Mutex
+	user map[string]*bucket
+
+	userWindow time.Duration // 用户令牌桶从空到补满的时间
+	swept      time.Time     // 上次清理空闲用户令牌桶的时间
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(cfg RateLimit) *RateLimiter {
+	if cfg.BackoffFactor <= 0 || cfg.BackoffFactor >= 1 {
+		cfg.BackoffFactor = 0.5
+	}
+	if cfg.MinFactor <= 0 || cfg.MinFactor > 1 {
+		cfg.MinFactor = 0.1
+	}
+	if cfg.RecoverInterval <= 0 {
+		cfg.RecoverInterval = 10 * time.Second
+	}
+
+	l := &RateLimiter{cfg: cfg, user: make(map[string]*bucket)}
+	if cfg.AppQPS > 0 {
+		l.app = newBucket(cfg.AppQPS, cfg.AppBurst, &l.cfg)
+	}
+	if cfg.UserQPS > 0 {
+		l.userWindow = newBucket(cfg.UserQPS, cfg.UserBurst, &l.cfg).window()
+	}
+	return l
+}
+
+// Wait 阻塞直到应用和 openID 对应用户都有可用额度，openID 为空时只受应用维度限制
+//
+// ctx 被取消时立即返回 ctx.Err()，已占用的额度会被归还。
+func (l *RateLimiter) Wait(ctx context.Context, openID string) error {
+	now := time.Now()
+
+	buckets := make([]*bucket, 0, 2)
+	if l.app != nil {
+		buckets = append(buckets, l.app)
+	}
+	if b := l.userBucket(openID); b != nil {
+		buckets = append(buckets, b)
+	}
+
+	var wait time.Duration
+	for _, b := range buckets {
+		wait = max(wait, b.reserve(now))
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		for _, b := range buckets {
+			b.cancel()
+		}
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Throttle 报告 openID 的请求被服务端限流，降低应用和该用户的请求速率
+func (l *RateLimiter) Throttle(openID string) {
+	now := time.Now()
+	if l.app != nil {
+		l.app.throttle(now)
+	}
+	if b := l.userBucket(openID); b != nil {
+		b.throttle(now)
+	}
+}
+
+func (l *RateLimiter) userBucket(openID string) *bucket {
+	if openID == "" || l.cfg.UserQPS <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) >= l.userWindow {
+		l.evictIdle(now)
+	}
+	b, ok := l.user[openID]
+	if !ok {
+		b = newBucket(l.cfg.UserQPS, l.cfg.UserBurst, &l.cfg)
+		l.user[openID] = b
+	}
+	return b
+}
+
+// evictIdle 移除已补满且速率已恢复的用户令牌桶，避免服务大量用户时 user 无限增长
+//
+// 这些桶与新建的桶等价，移除后该用户下次请求会重新创建。调用方需持有 l.mu。
+func (l *RateLimiter) evictIdle(now time.Time) {
+	l.swept = now
+	for openID, b := range l.user {
+		if b.idle(now) {
+			delete(l.user, openID)
+		}
+	}
+}
+
+// bucket 令牌桶，tokens 可以为负数，表示已被预占的未来额度
+type bucket struct {
+	cfg *RateLimit
+
+	mu        sync.Mutex
+	limit     float64 // 配置速率
+	rate      float64 // 当前速率
+	burst     float64
+	tokens    float64
+	last      time.Time
+	throttled time.Time // 最近一次降速或恢复的时间
+}
+
+func newBucket(qps float64, burst int, cfg *RateLimit) *bucket {
+	b := float64(burst)
+	if b <= 0 {
+		b = math.Max(1, math.Ceil(qps))
+	}
+	return &bucket{cfg: cfg, limit: qps, rate: qps, burst: b, tokens: b, last: time.Now()}
+}
+
+// reserve 占用一个令牌，返回需要等待的时间
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.restore(now)
+	b.advance(now)
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// window 返回以配置速率从空桶补满的时间
+func (b *bucket) window() time.Duration {
+	return time.Duration(b.burst / b.limit * float64(time.Second))
+}
+
+// idle 判断令牌桶是否已补满且速率已恢复到配置值
+func (b *bucket) idle(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.restore(now)
+	b.advance(now)
+	return b.rate >= b.limit && b.tokens >= b.burst
+}
+
+func (b *bucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.tokens+1, b.burst)
+}
+
+func (b *bucket) throttle(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(now)
+	b.rate = math.Max(b.rate*b.cfg.BackoffFactor, b.limit*b.cfg.MinFactor)
+	b.tokens = math.Min(b.tokens, 0) // 丢弃积攒的突发额度
+	b.throttled = now
+}
+
+// advance 按当前速率补充令牌
+func (b *bucket) advance(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// restore 距上次限流足够久后逐步恢复速率
+func (b *bucket) restore(now time.Time) {
+	if b.rate >= b.limit || now.Sub(b.throttled) < b.cfg.RecoverInterval {
+		return
+	}
+
+	b.advance(now)
+	steps := float64(now.Sub(b.throttled) / b.cfg.RecoverInterval)
+	b.rate = math.Min(b.limit, b.rate+steps*b.limit*0.1)
+	b.throttled = now
+}
+
+// RateLimitTransport 在发送请求前等待限流额度的 http.RoundTripper
+//
+// 用户维度以请求头 Open-Id 区分；响应为 HTTP 429 或限流 ret 码时自动降低速率。
+type RateLimitTransport struct {
+	Base    http.RoundTripper
+	Limiter *RateLimiter
+}
+
+// NewRateLimitTransport 创建限流 Transport，base 为空时使用 http.DefaultTransport
+func NewRateLimitTransport(base http.RoundTripper, limiter *RateLimiter) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RateLimitTransport{Base: base, Limiter: limiter}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	openID := req.Header.Get("Open-Id")
+	if err := t.Limiter.Wait(req.Context(), openID); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("wait for rate limit: %w", err)
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		t.Limiter.Throttle(openID)
+	} else if resp.StatusCode == http.StatusOK {
+		if ret, ok := peekRet(resp); ok && ret == constant.RetRateLimited {
+			t.Limiter.Throttle(openID)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterUserBudget(t *testing.T) {
+	t.Parallel()
+
+	l := NewRateLimiter(RateLimit{UserQPS: 20, UserBurst: 1})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx, "u1"); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	// 突发额度为1，后两次各需等待 50ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("3 waits took %v, want >= 100ms", elapsed)
+	}
+
+	// 其他用户不受影响
+	start = time.Now()
+	if err := l.Wait(ctx, "u2"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Fatalf("other user waited %v", elapsed)
+	}
+}
+
+func TestRateLimiterHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	l := NewRateLimiter(RateLimit{AppQPS: 0.1, AppBurst: 1})
+	if err := l.Wait(context.Background(), ""); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want DeadlineExceeded", err)
+	}
+
+	// 取消的等待需归还额度
+	if got := l.app.tokens; got < -0.01 {
+		t.Fatalf("tokens after cancel = %v, want >= 0", got)
+	}
+}
+
+func TestRateLimiterThrottleAndRecover(t *testing.T) {
+	t.Parallel()
+
+	l := NewRateLimiter(RateLimit{AppQPS: 100, RecoverInterval: time.Second})
+	b := l.app
+
+	l.Throttle("")
+	l.Throttle("")
+	if b.rate != 25 {
+		t.Fatalf("rate after 2 throttles = %v, want 25", b.rate)
+	}
+	for i := 0; i < 10; i++ {
+		l.Throttle("")
+	}
+	if b.rate != 10 {
+		t.Fatalf("rate floor = %v, want 10", b.rate)
+	}
+
+	b.restore(b.throttled.Add(3 * time.Second))
+	if b.rate != 40 {
+		t.Fatalf("rate after recovering 3 intervals = %v, want 40", b.rate)
+	}
+	b.restore(b.throttled.Add(time.Minute))
+	if b.rate != 100 {
+		t.Fatalf("rate after full recovery = %v, want 100", b.rate)
+	}
+}
+
+func TestRateLimiterEvictsIdleUsers(t *testing.T) {
+	t.Parallel()
+
+	// 补满周期为10ms
+	l := NewRateLimiter(RateLimit{UserQPS: 100, UserBurst: 1, RecoverInterval: time.Hour})
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(ctx, fmt.Sprintf("u%d", i)); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	l.Throttle("u0") // 降速中的用户不能被移除
+
+	time.Sleep(30 * time.Millisecond)
+	if err := l.Wait(ctx, "new"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.user) != 2 || l.user["u0"] == nil || l.user["new"] == nil {
+		t.Fatalf("user buckets = %d, want u0 and new", len(l.user))
+	}
+}
@@ -0,0 +1,250 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/chinahtl/tencent-doc-sdk/constant"
+)
+
+// RetryPolicy 请求重试策略
+//
+// 以下情况会触发重试：
+//   - 连接失败、连接被重置等网络错误（非幂等请求仅在连接建立失败时重试）
+//   - HTTP 状态码为 429，或 500、502、503、504（仅幂等请求）
+//   - 响应中的 ret 属于 RetryableRets
+//
+// 限流类错误（HTTP 429 或 ret 为限流码）表示请求未被处理，任何方法都会重试；
+// 503 仅在响应带有 Retry-After 时对非幂等请求重试。
+type RetryPolicy struct {
+	MaxAttempts    int           // 最大尝试次数（含首次请求），小于等于1时不重试
+	InitialBackoff time.Duration // 首次重试前的等待时间
+	MaxBackoff     time.Duration // 单次等待时间上限，同样限制 Retry-After
+	Multiplier     float64       // 退避倍数
+	Jitter         float64       // 随机抖动比例，取值 0~1
+	RetryableRets  []int         // 可重试的 ret 码
+	RetryPost      bool          // 是否对 POST 等非幂等请求的服务端错误进行重试
+}
+
+// DefaultRetryPolicy 返回默认重试策略：最多3次尝试，退避时间从200ms开始翻倍，上限5s
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableRets:  []int{constant.RetRateLimited},
+	}
+}
+
+// Backoff 返回第 attempt 次请求失败后（从1开始）的等待时间
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		d = d * (1 - jitter + 2*jitter*rand.Float64())
+	}
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) retryableRet(ret int) bool {
+	return slices.Contains(p.RetryableRets, ret)
+}
+
+// RetryTransport 按 RetryPolicy 重试请求的 http.RoundTripper
+type RetryTransport struct {
+	Base   http.RoundTripper
+	Policy *RetryPolicy
+}
+
+// NewRetryTransport 创建重试 Transport，base 为空时使用 http.DefaultTransport
+func NewRetryTransport(base http.RoundTripper, policy *RetryPolicy) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{Base: base, Policy: policy}
+}
+
+type retryPolicyKey struct{}
+
+// ContextWithRetryPolicy 返回携带重试策略的 ctx，RetryTransport 对使用该 ctx 的请求以 policy 代替自身的策略，
+// policy 为空时不重试
+func ContextWithRetryPolicy(ctx context.Context, policy *RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if policy, ok := req.Context().Value(retryPolicyKey{}).(*RetryPolicy); ok && policy != t.Policy {
+		override := RetryTransport{Base: t.Base, Policy: policy}
+		return override.roundTrip(req)
+	}
+	return t.roundTrip(req)
+}
+
+func (t *RetryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	// 请求体无法重放时不重试
+	if t.Policy == nil || t.Policy.MaxAttempts <= 1 || (req.Body != nil && req.GetBody == nil) {
+		return t.Base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		if attempts, ok := ctx.Value(attemptsKey{}).(*int); ok {
+			*attempts = attempt
+		}
+		try := req.WithContext(withAttempt(ctx, attempt))
+		if attempt > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			try.Body = body
+		}
+
+		resp, err := t.Base.RoundTrip(try)
+		retry, wait := t.classify(try, resp, err)
+		if !retry || attempt >= t.Policy.MaxAttempts {
+			return resp, err
+		}
+
+		if wait == 0 {
+			wait = t.Policy.Backoff(attempt)
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// classify 判断响应是否需要重试，返回服务端通过 Retry-After 指定的等待时间
+func (t *RetryTransport) classify(req *http.Request, resp *http.Response, err error) (bool, time.Duration) {
+	idempotent := isIdempotent(req.Method) || t.Policy.RetryPost
+
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false, 0
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true, 0 // 连接未建立，请求一定没有发出
+		}
+		return idempotent, 0
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true, t.retryAfter(resp)
+	case http.StatusServiceUnavailable:
+		// 代理返回的503不能保证非幂等请求没有执行，仅在服务端通过 Retry-After 明确要求重试时重试
+		return idempotent || resp.Header.Get("Retry-After") != "", t.retryAfter(resp)
+	case http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusInternalServerError:
+		return idempotent, t.retryAfter(resp)
+	case http.StatusOK:
+		if len(t.Policy.RetryableRets) == 0 {
+			return false, 0
+		}
+		ret, ok := peekRet(resp)
+		return ok && t.Policy.retryableRet(ret), t.retryAfter(resp)
+	}
+	return false, 0
+}
+
+func (t *RetryTransport) retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	var d time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		d = time.Until(at)
+	}
+
+	if d < 0 {
+		return 0
+	}
+	if t.Policy.MaxBackoff > 0 && d > t.Policy.MaxBackoff {
+		d = t.Policy.MaxBackoff
+	}
+	return d
+}
+
+// peekRet 读取 JSON 响应中的 ret 字段，并将响应体恢复为可再次读取
+func peekRet(resp *http.Response) (int, bool) {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return 0, false
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+
+	var envelope struct {
+		Ret int `json:"ret"`
+	}
+	if json.Unmarshal(body, &envelope) != nil {
+		return 0, false
+	}
+	return envelope.Ret, true
+}
+
+type attemptKey struct{}
+
+// attemptsKey Executor 在 ctx 中放入的计数器，RetryTransport 将实际尝试次数写入其中
+type attemptsKey struct{}
+
+// withAttempt 在 ctx 中记录当前是第几次尝试，供内层 Transport 读取
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFrom 返回 ctx 中记录的尝试次数，未经过 RetryTransport 时为1
+func attemptFrom(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package util
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 10 * time.Millisecond
+	return p
+}
+
+func TestRetryTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		method    string
+		responses []func(w http.ResponseWriter)
+		wantCalls int32
+		wantRet   string
+	}{
+		{
+			name:   "retries 503",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) { w.Write([]byte(`{"ret":0}`)) },
+			},
+			wantCalls: 2,
+			wantRet:   `{"ret":0}`,
+		},
+		{
+			name:   "retries rate limited ret on POST",
+			method: http.MethodPost,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Content-Type", "application/json; charset=utf-8")
+					w.Header().Set("Retry-After", "60")
+					w.Write([]byte(`{"ret":400009,"msg":"limited"}`))
+				},
+				func(w http.ResponseWriter) { w.Write([]byte(`{"ret":0}`)) },
+			},
+			wantCalls: 2,
+			wantRet:   `{"ret":0}`,
+		},
+		{
+			name:   "does not retry POST 503",
+			method: http.MethodPost,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			},
+			wantCalls: 1,
+		},
+		{
+			name:   "retries POST 503 with Retry-After",
+			method: http.MethodPost,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(http.StatusServiceUnavailable)
+				},
+				func(w http.ResponseWriter) { w.Write([]byte(`{"ret":0}`)) },
+			},
+			wantCalls: 2,
+			wantRet:   `{"ret":0}`,
+		},
+		{
+			name:   "does not retry POST 500",
+			method: http.MethodPost,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+			},
+			wantCalls: 1,
+		},
+		{
+			name:   "gives up after max attempts",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.Write([]byte(`{"ret":0}`)) },
+			},
+			wantCalls: 3,
+		},
+		{
+			name:   "keeps non-retryable ret body",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"ret":400004,"msg":"not found"}`))
+				},
+			},
+			wantCalls: 1,
+			wantRet:   `{"ret":400004,"msg":"not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := calls.Add(1)
+				if r.Method == http.MethodPost {
+					if err := r.ParseForm(); err != nil || r.PostForm.Get("k") != "v" {
+						t.Errorf("attempt %d lost request body", n)
+					}
+				}
+				tt.responses[n-1](w)
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: NewRetryTransport(nil, testRetryPolicy())}
+			req, _ := http.NewRequest(tt.method, srv.URL, strings.NewReader("k=v"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			start := time.Now()
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if time.Since(start) > time.Second {
+				t.Fatal("Retry-After not capped by MaxBackoff")
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantRet != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.wantRet {
+					t.Fatalf("body = %q, want %q", body, tt.wantRet)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := testRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, policy)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("Do() error = nil, want context error")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.Backoff(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("Backoff(1) with jitter = %v, want within [50ms, 150ms]", got)
+		}
+	}
+}
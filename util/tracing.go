@@ -0,0 +1,36 @@
+package util
+
+import (
+	"net/http"
+
+	"github.com/chinahtl/tencent-doc-sdk/telemetry"
+)
+
+// TracingTransport 将链路上下文注入请求头的 http.RoundTripper
+//
+// 位于 RetryTransport 之内时，每次重试都会更新当前 span 的 tencentdoc.retries 属性。
+type TracingTransport struct {
+	Base   http.RoundTripper
+	Tracer telemetry.Tracer
+}
+
+// NewTracingTransport 创建链路追踪 Transport，base 为空时使用 http.DefaultTransport
+func NewTracingTransport(base http.RoundTripper, tracer telemetry.Tracer) *TracingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &TracingTransport{Base: base, Tracer: tracer}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *TracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if span := telemetry.SpanFromContext(ctx); span != nil {
+		span.SetAttributes(telemetry.Int(telemetry.AttrRetries, attemptFrom(ctx)-1))
+	}
+
+	// RoundTripper 不应修改传入的请求
+	req = req.Clone(ctx)
+	t.Tracer.Inject(ctx, req.Header)
+	return t.Base.RoundTrip(req)
+}